@@ -7,6 +7,8 @@ require (
 	github.com/machinebox/graphql v0.2.2
 	github.com/stretchr/testify v1.10.0
 	go.uber.org/fx v1.23.0
+	golang.org/x/text v0.22.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -18,5 +20,4 @@ require (
 	go.uber.org/multierr v1.10.0 // indirect
 	go.uber.org/zap v1.26.0 // indirect
 	golang.org/x/sys v0.0.0-20220412211240-33da011f77ad // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
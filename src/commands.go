@@ -0,0 +1,558 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/azhu2/bongo/src/config/dates"
+	"github.com/azhu2/bongo/src/config/secrets"
+	"github.com/azhu2/bongo/src/controller/audit"
+	"github.com/azhu2/bongo/src/controller/backfill"
+	"github.com/azhu2/bongo/src/controller/parser"
+	"github.com/azhu2/bongo/src/controller/query"
+	"github.com/azhu2/bongo/src/controller/solver"
+	"github.com/azhu2/bongo/src/controller/wordlist"
+	"github.com/azhu2/bongo/src/entity"
+	"github.com/azhu2/bongo/src/gateway/gameimporter"
+	"github.com/azhu2/bongo/src/gateway/overrides"
+	"github.com/azhu2/bongo/src/handler"
+)
+
+type command struct {
+	description string
+	// setup registers the command's flags. The returned func is called after they are parsed
+	// and adds whatever the command needs to the app.
+	setup func(flags *flag.FlagSet, format parser.Format) func() fx.Option
+}
+
+var commandNames = []string{"solve", "words", "anagrams", "placements", "audit", "dict", "puzzles", "backfill", "serve"}
+
+var commands = map[string]command{
+	"solve": {
+		description: "solve a day's puzzle, today's by default",
+		setup:       setupSolve,
+	},
+	"words": {
+		description: "list words matching a pattern like S?O?N, _?AT_ for blanks",
+		setup:       setupWords,
+	},
+	"anagrams": {
+		description: "list words buildable from a board's tiles, by length and tile value",
+		setup:       setupAnagrams,
+	},
+	"placements": {
+		description: "rank words by their best score on each row of a board",
+		setup:       setupPlacements,
+	},
+	"audit": {
+		description: "check the word list against the words in archived puzzle metadata",
+		setup:       setupAudit,
+	},
+	"dict": {
+		description: "show a word's status, or add, remove, common, uncommon or reset it in your overrides",
+		setup:       setupDict,
+	},
+	"puzzles": {
+		description: "list the day's bongo puzzles to pick from with -puzzle",
+		setup:       setupPuzzles,
+	},
+	"backfill": {
+		description: "archive the boards for a range of dates",
+		setup:       setupBackfill,
+	},
+	"serve": {
+		description: "serve solve and words as a JSON API",
+		setup:       setupServe,
+	},
+}
+
+// puzzmoOptions are what it takes to fetch boards from Puzzmo and solve them
+func puzzmoOptions() fx.Option {
+	return fx.Options(
+		handler.Module,
+		gameimporter.Module,
+		gameimporter.ArchiveModule,
+		query.Module,
+		dates.Module,
+		secrets.Module,
+		solver.Module,
+	)
+}
+
+// runOnce runs a command to completion, then stops the app
+func runOnce(run func(ctx context.Context) error) fx.Option {
+	return fx.Invoke(func(lifecycle fx.Lifecycle, shutdowner fx.Shutdowner) {
+		lifecycle.Append(fx.StartHook(func(_ context.Context) {
+			go func() {
+				if err := run(context.Background()); err != nil {
+					slog.Error("command failed", "err", err)
+					shutdowner.Shutdown(fx.ExitCode(1))
+					return
+				}
+				shutdowner.Shutdown()
+			}()
+		}))
+	})
+}
+
+func setupSolve(flags *flag.FlagSet, format parser.Format) func() fx.Option {
+	date := flags.String("date", "today", "day to solve, like 2024-12-23, yesterday or -3")
+
+	return func() fx.Option {
+		var h handler.Handler
+		var p parser.Controller
+		return fx.Options(
+			puzzmoOptions(),
+			fx.Populate(&h, &p),
+			runOnce(func(ctx context.Context) error {
+				start := time.Now()
+				solutions, score, err := h.Solve(ctx, *date)
+				if err != nil {
+					return fmt.Errorf("error in solver %w", err)
+				}
+				slog.Info("solution found", "score", score, "time", time.Since(start))
+				for _, solution := range solutions {
+					out, err := p.SerializeSolution(ctx, solution, format)
+					if err != nil {
+						slog.Error("unable to serialize solution", "err", err)
+						continue
+					}
+					fmt.Println(out)
+				}
+				return nil
+			}),
+		)
+	}
+}
+
+func setupWords(flags *flag.FlagSet, _ parser.Format) func() fx.Option {
+	tiles := flags.String("tiles", "", "only use these letters, like AABCE (default any letters)")
+	boardPath := flags.String("board", "", "board file to score words against")
+	row := flags.Int("row", 0, "board row to score words on, from 0 at the top")
+	limit := flags.Int("limit", 50, "most words to list, 0 for all")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s words [flags] PATTERN\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+
+	return func() fx.Option {
+		if flags.NArg() != 1 {
+			flags.Usage()
+			os.Exit(2)
+		}
+		pattern := flags.Arg(0)
+		var q query.Controller
+		var p parser.Controller
+		return fx.Options(
+			query.Module,
+			fx.Populate(&q, &p),
+			runOnce(func(ctx context.Context) error {
+				request := query.Query{Pattern: pattern, Row: *row}
+				if *tiles != "" {
+					request.Tiles = query.ParseTiles(*tiles)
+				}
+				if *boardPath != "" {
+					board, err := loadBoard(ctx, p, *boardPath)
+					if err != nil {
+						return err
+					}
+					request.Board = board
+				}
+				matches, err := q.Words(ctx, request)
+				if err != nil {
+					return err
+				}
+				if *limit > 0 && len(matches) > *limit {
+					matches = matches[:*limit]
+				}
+				for _, match := range matches {
+					if request.Board == nil {
+						fmt.Println(match.Word)
+					} else {
+						fmt.Printf("%s\t%d\n", match.Word, match.Score)
+					}
+				}
+				return nil
+			}),
+		)
+	}
+}
+
+func setupAnagrams(flags *flag.FlagSet, _ parser.Format) func() fx.Option {
+	boardPath := flags.String("board", "", "board file to take tiles from (required)")
+	noWildcard := flags.Bool("no-wildcard", false, "only list words that need no wildcard")
+	limit := flags.Int("limit", 20, "most words to list per length, 0 for all")
+
+	return func() fx.Option {
+		if *boardPath == "" || flags.NArg() != 0 {
+			flags.Usage()
+			os.Exit(2)
+		}
+		var q query.Controller
+		var p parser.Controller
+		return fx.Options(
+			query.Module,
+			fx.Populate(&q, &p),
+			runOnce(func(ctx context.Context) error {
+				board, err := loadBoard(ctx, p, *boardPath)
+				if err != nil {
+					return err
+				}
+				for _, group := range q.Anagrams(ctx, board, !*noWildcard) {
+					words := group.Words
+					if *limit > 0 && len(words) > *limit {
+						words = words[:*limit]
+					}
+					fmt.Printf("%d letters (%d):\n", group.Length, len(group.Words))
+					for _, anagram := range words {
+						wildcard := ""
+						if anagram.Wildcard {
+							wildcard = " *"
+						}
+						fmt.Printf("  %s\t%d%s\n", anagram.Word, anagram.Value, wildcard)
+					}
+				}
+				return nil
+			}),
+		)
+	}
+}
+
+func setupPlacements(flags *flag.FlagSet, _ parser.Format) func() fx.Option {
+	boardPath := flags.String("board", "", "board file to place words on (required)")
+	row := flags.Int("row", -1, "rank by score on this row instead of the best row")
+	limit := flags.Int("limit", 50, "most words to list, 0 for all")
+
+	return func() fx.Option {
+		if *boardPath == "" || flags.NArg() != 0 {
+			flags.Usage()
+			os.Exit(2)
+		}
+		var q query.Controller
+		var p parser.Controller
+		return fx.Options(
+			query.Module,
+			fx.Populate(&q, &p),
+			runOnce(func(ctx context.Context) error {
+				board, err := loadBoard(ctx, p, *boardPath)
+				if err != nil {
+					return err
+				}
+				if *row >= board.Size.Rows {
+					return fmt.Errorf("row %d is not on the board", *row)
+				}
+				placements, err := q.Placements(ctx, board)
+				if err != nil {
+					return err
+				}
+				if *row >= 0 {
+					slices.SortStableFunc(placements, func(a, b query.Placement) int {
+						return b.Rows[*row].Score - a.Rows[*row].Score
+					})
+				}
+				if *limit > 0 && len(placements) > *limit {
+					placements = placements[:*limit]
+				}
+
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprint(w, "WORD\tBEST")
+				for r := range board.Size.Rows {
+					fmt.Fprintf(w, "\tROW %d", r)
+				}
+				fmt.Fprintln(w, "\tBONUS")
+				for _, placement := range placements {
+					fmt.Fprintf(w, "%s\t%d", placement.Word, placement.Best.Score)
+					for _, rowPlacement := range placement.Rows {
+						fmt.Fprintf(w, "\t%d@%d", rowPlacement.Score, rowPlacement.Col)
+					}
+					if placement.Bonus {
+						fmt.Fprintf(w, "\t%d\n", placement.BonusScore)
+					} else {
+						fmt.Fprintln(w, "\t-")
+					}
+				}
+				return w.Flush()
+			}),
+		)
+	}
+}
+
+func setupAudit(flags *flag.FlagSet, _ parser.Format) func() fx.Option {
+	dir := flags.String("dir", "", "directory of archived puzzle files (default the board archive)")
+	showUnseen := flags.Bool("unseen", false, "also list word list entries no puzzle has used")
+
+	return func() fx.Option {
+		var a audit.Controller
+		var p parser.Controller
+		var archiveConfig gameimporter.ArchiveConfig
+		return fx.Options(
+			audit.Module,
+			fx.Populate(&a, &p, &archiveConfig),
+			runOnce(func(ctx context.Context) error {
+				if *dir == "" {
+					archiveDir, err := archiveConfig.ResolveDir()
+					if err != nil {
+						return err
+					}
+					*dir = archiveDir
+				}
+				paths := []string{}
+				for _, extension := range gameimporter.BoardExtensions {
+					matches, err := filepath.Glob(filepath.Join(*dir, "*"+extension))
+					if err != nil {
+						return err
+					}
+					paths = append(paths, matches...)
+				}
+				slices.Sort(paths)
+				metadata := []*entity.Metadata{}
+				for _, path := range paths {
+					raw, err := os.ReadFile(path)
+					if err != nil {
+						return fmt.Errorf("unable to read puzzle %w", err)
+					}
+					// Only the Puzzmo text format carries metadata
+					if parser.DetectFormat(string(raw)) != parser.FormatText {
+						continue
+					}
+					puzzle, err := p.ParseMetadata(ctx, string(raw))
+					if errors.Is(err, parser.ErrNoMetadata) {
+						continue
+					}
+					if err != nil {
+						return fmt.Errorf("unable to parse metadata in %s %w", path, err)
+					}
+					metadata = append(metadata, puzzle)
+				}
+				if len(metadata) == 0 {
+					return fmt.Errorf("no puzzle metadata found in %s", *dir)
+				}
+
+				report := a.Audit(ctx, metadata)
+				fmt.Printf("%d accepted words across %d puzzles\n", report.Accepted, report.Puzzles)
+				fmt.Printf("\nMissing from word list (%d):\n", len(report.Missing))
+				for _, missing := range report.Missing {
+					fmt.Printf("  %s\t%d\n", missing.Word, missing.Puzzles)
+				}
+				fmt.Printf("\nOutside playable lengths (%d): %s\n", len(report.Unplayable), strings.Join(report.Unplayable, ", "))
+				fmt.Printf("\nNever seen in a puzzle: %d\n", len(report.Unseen))
+				if *showUnseen {
+					for _, word := range report.Unseen {
+						fmt.Printf("  %s\n", word)
+					}
+				}
+				return nil
+			}),
+		)
+	}
+}
+
+func setupDict(flags *flag.FlagSet, _ parser.Format) func() fx.Option {
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s dict show|add|remove|common|uncommon|reset WORD\n", os.Args[0])
+	}
+
+	return func() fx.Option {
+		if flags.NArg() != 2 {
+			flags.Usage()
+			os.Exit(2)
+		}
+		action, word := flags.Arg(0), strings.ToUpper(flags.Arg(1))
+		if action != "show" && action != "reset" && !slices.Contains(entity.OverrideActions, entity.OverrideAction(action)) {
+			flags.Usage()
+			os.Exit(2)
+		}
+		var w wordlist.Controller
+		var o overrides.Gateway
+		return fx.Options(
+			fx.Populate(&w, &o),
+			runOnce(func(ctx context.Context) error {
+				switch action {
+				case "show":
+				case "reset":
+					if err := w.ResetOverride(ctx, word); err != nil {
+						return err
+					}
+				default:
+					if err := w.SetOverride(ctx, word, entity.OverrideAction(action)); err != nil {
+						return err
+					}
+				}
+				status, err := w.Status(ctx, word)
+				if err != nil {
+					return err
+				}
+				printWordStatus(status, o.Path())
+				return nil
+			}),
+		)
+	}
+}
+
+func printWordStatus(status *wordlist.WordStatus, overridesPath string) {
+	inList := "not in word list"
+	if status.InList {
+		inList = "in word list"
+	}
+	fmt.Printf("%s: %s\n", status.Word, inList)
+	if status.InBase {
+		fmt.Println("  in the base list")
+	} else {
+		fmt.Println("  not in the base list")
+	}
+	if status.Membership != "" {
+		fmt.Printf("  %sed by %s\n", strings.TrimSuffix(string(status.Membership), "e"), overridesPath)
+	}
+	if !status.Playable {
+		fmt.Println("  skipped since the rules don't allow words this long")
+	}
+	if status.InList {
+		common := "common"
+		if !status.Common {
+			common = "uncommon"
+		}
+		if status.Commonness != "" {
+			fmt.Printf("  %s, marked by %s\n", common, overridesPath)
+		} else {
+			fmt.Printf("  %s by default\n", common)
+		}
+	}
+}
+
+func setupPuzzles(flags *flag.FlagSet, _ parser.Format) func() fx.Option {
+	date := flags.String("date", "today", "day to list, like 2024-12-23, yesterday or -3")
+
+	return func() fx.Option {
+		var l gameimporter.PuzzleLister
+		var d dates.Resolver
+		return fx.Options(
+			puzzmoOptions(),
+			fx.Populate(&l, &d),
+			runOnce(func(ctx context.Context) error {
+				day, err := d.Resolve(*date)
+				if err != nil {
+					return err
+				}
+				puzzles, err := l.ListPuzzles(ctx, day)
+				if err != nil {
+					return err
+				}
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "INDEX\tSLUG\tURL PATH")
+				for i, puzzle := range puzzles {
+					fmt.Fprintf(w, "%d\t%s\t%s\n", i, puzzle.Slug, puzzle.URLPath)
+				}
+				return w.Flush()
+			}),
+		)
+	}
+}
+
+func setupBackfill(flags *flag.FlagSet, _ parser.Format) func() fx.Option {
+	from := flags.String("from", "", "first date to archive, like 2024-12-23 or -30")
+	to := flags.String("to", "today", "last date to archive")
+	interval := flags.Duration("interval", 2*time.Second, "least time between fetches from Puzzmo")
+
+	return func() fx.Option {
+		if *from == "" {
+			fmt.Fprintln(flags.Output(), "-from is required")
+			flags.PrintDefaults()
+			os.Exit(2)
+		}
+		var b backfill.Controller
+		var d dates.Resolver
+		return fx.Options(
+			puzzmoOptions(),
+			backfill.Module,
+			fx.Supply(backfill.Config{Interval: *interval}),
+			fx.Populate(&b, &d),
+			runOnce(func(ctx context.Context) error {
+				first, err := resolveDay(d, *from)
+				if err != nil {
+					return err
+				}
+				last, err := resolveDay(d, *to)
+				if err != nil {
+					return err
+				}
+				report, err := b.Backfill(ctx, first, last)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("imported %d, already archived %d, failed %d\n", len(report.Imported), len(report.Skipped), len(report.Failed))
+				for _, date := range report.Imported {
+					if source, ok := report.Sources[date]; ok {
+						fmt.Printf("  %s: from %s\n", date, source)
+					}
+				}
+				for _, failure := range report.Failed {
+					fmt.Printf("  %s: %v\n", failure.Date, failure.Err)
+				}
+				if len(report.Failed) > 0 {
+					return fmt.Errorf("%d dates failed, run again to retry them", len(report.Failed))
+				}
+				return nil
+			}),
+		)
+	}
+}
+
+// resolveDay is the Puzzmo day for a date as the user typed it
+func resolveDay(d dates.Resolver, value string) (time.Time, error) {
+	date, err := d.Resolve(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(dates.Format, date)
+}
+
+func setupServe(flags *flag.FlagSet, _ parser.Format) func() fx.Option {
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+
+	return func() fx.Option {
+		return fx.Options(
+			puzzmoOptions(),
+			handler.HTTPModule,
+			fx.Invoke(func(lifecycle fx.Lifecycle, mux http.Handler) {
+				server := &http.Server{Addr: *addr, Handler: mux}
+				lifecycle.Append(fx.Hook{
+					OnStart: func(_ context.Context) error {
+						listener, err := net.Listen("tcp", *addr)
+						if err != nil {
+							return err
+						}
+						slog.Info("serving", "addr", listener.Addr().String())
+						go func() {
+							if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+								slog.Error("server stopped", "err", err)
+							}
+						}()
+						return nil
+					},
+					OnStop: server.Shutdown,
+				})
+			}),
+		)
+	}
+}
+
+func loadBoard(ctx context.Context, p parser.Controller, path string) (*entity.Board, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read board %w", err)
+	}
+	return p.ParseBoard(ctx, string(raw))
+}
@@ -0,0 +1,112 @@
+package dates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	// Time zones are looked up by name, which needs tzdata even where the OS has none
+	_ "time/tzdata"
+
+	"go.uber.org/fx"
+)
+
+// Format is how Puzzmo, the archive and testdata write days
+const Format = "2006-01-02"
+
+// DefaultTimeZone is where Puzzmo's day rolls over
+const DefaultTimeZone = "America/Chicago"
+
+var Module = fx.Module("dates",
+	fx.Provide(New),
+)
+
+// Resolver turns the dates users type into Puzzmo days
+type Resolver interface {
+	// Today is the current Puzzmo day
+	Today() string
+	// Resolve accepts "" or today, yesterday, a day offset like -3, or a day like 2024-12-23
+	Resolve(value string) (string, error)
+}
+
+type Clock interface {
+	Now() time.Time
+}
+
+type Config struct {
+	// TimeZone is an IANA name like America/Chicago. Defaults to DefaultTimeZone.
+	TimeZone string
+}
+
+type Params struct {
+	fx.In
+
+	Config Config `optional:"true"`
+	// Clock defaults to the system clock. Optional.
+	Clock Clock `optional:"true"`
+}
+
+type Result struct {
+	fx.Out
+
+	Resolver
+}
+
+type resolver struct {
+	location *time.Location
+	clock    Clock
+}
+
+type systemClock struct{}
+
+func New(p Params) (Result, error) {
+	timeZone := p.Config.TimeZone
+	if timeZone == "" {
+		timeZone = DefaultTimeZone
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return Result{}, fmt.Errorf("unknown time zone %s %w", timeZone, err)
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return Result{
+		Resolver: &resolver{
+			location: location,
+			clock:    clock,
+		},
+	}, nil
+}
+
+func (r *resolver) Today() string {
+	return r.offset(0)
+}
+
+func (r *resolver) Resolve(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "", "today":
+		return r.offset(0), nil
+	case "yesterday":
+		return r.offset(-1), nil
+	}
+	if days, err := strconv.Atoi(value); err == nil {
+		return r.offset(days), nil
+	}
+	if _, err := time.Parse(Format, value); err != nil {
+		return "", fmt.Errorf("invalid date %q, expected today, yesterday, an offset like -3 or a day like 2024-12-23", value)
+	}
+	return value, nil
+}
+
+// offset is the Puzzmo day some number of days from today
+func (r *resolver) offset(days int) string {
+	// Calendar arithmetic in the Puzzmo time zone, so DST changes don't shift the day
+	return r.clock.Now().In(r.location).AddDate(0, 0, days).Format(Format)
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
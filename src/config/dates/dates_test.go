@@ -0,0 +1,65 @@
+package dates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func newResolver(t *testing.T, now string, timeZone string) Resolver {
+	parsed, err := time.Parse(time.RFC3339, now)
+	require.NoError(t, err)
+	result, err := New(Params{Config: Config{TimeZone: timeZone}, Clock: fixedClock(parsed)})
+	require.NoError(t, err)
+	return result.Resolver
+}
+
+func TestResolve(t *testing.T) {
+	// Already the 24th in UTC, but still the 23rd in Chicago
+	r := newResolver(t, "2024-12-24T03:00:00Z", "")
+	assert.Equal(t, "2024-12-23", r.Today())
+
+	tests := map[string]string{
+		"":           "2024-12-23",
+		"today":      "2024-12-23",
+		" Yesterday": "2024-12-22",
+		"-3":         "2024-12-20",
+		"0":          "2024-12-23",
+		"+1":         "2024-12-24",
+		"-23":        "2024-11-30",
+		"2024-01-02": "2024-01-02",
+	}
+	for value, expected := range tests {
+		date, err := r.Resolve(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, date, value)
+	}
+
+	for _, value := range []string{"tomorow", "2024-13-01", "12/23/2024", "../2024-12-23"} {
+		_, err := r.Resolve(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestResolve_TimeZone(t *testing.T) {
+	r := newResolver(t, "2024-12-24T03:00:00Z", "UTC")
+	assert.Equal(t, "2024-12-24", r.Today())
+
+	// Days are counted on the calendar, so the hour lost to DST doesn't matter
+	r = newResolver(t, "2024-03-11T05:30:00Z", "America/Chicago")
+	assert.Equal(t, "2024-03-11", r.Today())
+	date, err := r.Resolve("yesterday")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-10", date)
+
+	_, err = New(Params{Config: Config{TimeZone: "Mars/Olympus_Mons"}})
+	assert.Error(t, err)
+}
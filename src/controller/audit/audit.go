@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.uber.org/fx"
+
+	"github.com/azhu2/bongo/src/entity"
+)
+
+var Module = fx.Module("audit",
+	fx.Provide(New),
+)
+
+type Controller interface {
+	// Audit compares the words Puzzmo accepted in puzzle metadata with the word list
+	Audit(ctx context.Context, metadata []*entity.Metadata) *Report
+}
+
+type Report struct {
+	// Puzzles is how many metadata sections were checked
+	Puzzles int
+	// Accepted is how many distinct words the metadata mentions
+	Accepted int
+	// Missing are accepted words the word list lacks, most often seen first
+	Missing []WordCount
+	// Unplayable are accepted words outside the rules' lengths, which the word list skips on purpose
+	Unplayable []string
+	// Unseen are words in the word list that no metadata mentions, alphabetically
+	Unseen []string
+}
+
+type WordCount struct {
+	Word string
+	// Puzzles is how many puzzles mentioned the word
+	Puzzles int
+}
+
+type Params struct {
+	fx.In
+
+	Rules    entity.Rules
+	WordList *entity.WordList
+}
+
+type Result struct {
+	fx.Out
+
+	Controller
+}
+
+type controller struct {
+	rules    entity.Rules
+	wordList *entity.WordList
+}
+
+func New(p Params) (Result, error) {
+	return Result{
+		Controller: &controller{
+			rules:    p.Rules,
+			wordList: p.WordList,
+		},
+	}, nil
+}
+
+func (c *controller) Audit(_ context.Context, metadata []*entity.Metadata) *Report {
+	// Words can repeat within a puzzle, so count each puzzle once
+	puzzles := map[string]int{}
+	for _, puzzle := range metadata {
+		seen := map[string]bool{}
+		for _, word := range puzzle.Words() {
+			word = strings.ToUpper(word)
+			if !seen[word] {
+				seen[word] = true
+				puzzles[word]++
+			}
+		}
+	}
+
+	report := Report{
+		Puzzles:    len(metadata),
+		Accepted:   len(puzzles),
+		Missing:    []WordCount{},
+		Unplayable: []string{},
+		Unseen:     []string{},
+	}
+	for _, word := range slices.Sorted(maps.Keys(puzzles)) {
+		switch {
+		case !c.rules.IsPlayableLength(len(word)):
+			report.Unplayable = append(report.Unplayable, word)
+		case !c.wordList.Contains(word):
+			report.Missing = append(report.Missing, WordCount{Word: word, Puzzles: puzzles[word]})
+		}
+	}
+	// Stable, so ties stay alphabetical
+	slices.SortStableFunc(report.Missing, func(a, b WordCount) int {
+		return b.Puzzles - a.Puzzles
+	})
+
+	for _, word := range c.wordList.Words() {
+		if puzzles[word] == 0 {
+			report.Unseen = append(report.Unseen, word)
+		}
+	}
+	return &report
+}
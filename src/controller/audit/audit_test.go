@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azhu2/bongo/src/controller/wordlist"
+	"github.com/azhu2/bongo/src/entity"
+	"github.com/azhu2/bongo/testdata"
+)
+
+func TestAudit(t *testing.T) {
+	ctx := context.Background()
+	rules := entity.DefaultRules()
+	builder, err := wordlist.New(wordlist.Params{Rules: rules, Importer: testdata.StaticWordList{"CAT", "DOG", "EMU", "OWL"}})
+	require.NoError(t, err)
+	list, err := builder.BuildWordList(ctx)
+	require.NoError(t, err)
+	result, err := New(Params{Rules: rules, WordList: list})
+	require.NoError(t, err)
+
+	report := result.Controller.Audit(ctx, []*entity.Metadata{
+		{
+			Best:       entity.MetadataSolution{Words: []string{"CAT", "YAK"}, BonusWord: "GNU"},
+			ScoreBands: []entity.ScoreBand{{Low: 100, High: 200, Words: []string{"YAK", "OX"}}},
+		},
+		{
+			Worst: entity.MetadataSolution{Words: []string{"DOG", "YAK", "ZEBRAS"}},
+		},
+	})
+	assert.Equal(t, &Report{
+		Puzzles:  2,
+		Accepted: 6,
+		// YAK is counted once for the first puzzle even though it appears twice
+		Missing:    []WordCount{{Word: "YAK", Puzzles: 2}, {Word: "GNU", Puzzles: 1}},
+		Unplayable: []string{"OX", "ZEBRAS"},
+		Unseen:     []string{"EMU", "OWL"},
+	}, report)
+}
@@ -0,0 +1,122 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/azhu2/bongo/src/config/dates"
+	"github.com/azhu2/bongo/src/gateway/gameimporter"
+	"github.com/azhu2/bongo/src/util/sleep"
+)
+
+// ErrNoArchive means boards would be imported with nowhere to keep them
+var ErrNoArchive = errors.New("backfill needs the board archive enabled")
+
+var Module = fx.Module("backfill",
+	fx.Provide(New),
+)
+
+type Controller interface {
+	// Backfill archives the board for every date from first to last, inclusive. Dates already
+	// archived are skipped, so an interrupted backfill picks up where it left off.
+	Backfill(ctx context.Context, first, last time.Time) (*Report, error)
+}
+
+type Report struct {
+	// Imported are the dates newly archived
+	Imported []string
+	// Sources names where each imported date's board came from, when the importer can tell
+	Sources map[string]string
+	// Skipped are the dates that were already archived
+	Skipped []string
+	Failed  []Failure
+}
+
+type Failure struct {
+	Date string
+	Err  error
+}
+
+type Config struct {
+	// Interval is the least time between imports, to go easy on Puzzmo. Archived dates don't count.
+	Interval time.Duration
+}
+
+type Params struct {
+	fx.In
+
+	Importer gameimporter.Gateway
+	Config   Config `optional:"true"`
+}
+
+type Result struct {
+	fx.Out
+
+	Controller
+}
+
+type controller struct {
+	importer gameimporter.Gateway
+	interval time.Duration
+}
+
+func New(p Params) (Result, error) {
+	return Result{
+		Controller: &controller{
+			importer: p.Importer,
+			interval: p.Config.Interval,
+		},
+	}, nil
+}
+
+func (c *controller) Backfill(ctx context.Context, first, last time.Time) (*Report, error) {
+	archiver, ok := c.importer.(gameimporter.Archiver)
+	if !ok {
+		return nil, ErrNoArchive
+	}
+	if last.Before(first) {
+		return nil, fmt.Errorf("backfill range ends %s before it starts %s", last.Format(dates.Format), first.Format(dates.Format))
+	}
+
+	report := &Report{
+		Imported: []string{},
+		Sources:  map[string]string{},
+		Skipped:  []string{},
+		Failed:   []Failure{},
+	}
+	var lastImport time.Time
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dates.Format)
+		if _, ok := archiver.ArchivedPath(date); ok {
+			report.Skipped = append(report.Skipped, date)
+			continue
+		}
+		if err := sleep.Until(ctx, lastImport.Add(c.interval)); err != nil {
+			return report, err
+		}
+		lastImport = time.Now()
+		imported, err := gameimporter.ImportSourcedBoard(ctx, c.importer, date)
+		if err != nil {
+			slog.Warn("unable to backfill board", "date", date, "err", err)
+			report.Failed = append(report.Failed, Failure{Date: date, Err: err})
+			continue
+		}
+		// The archive only warns when it can't save
+		path, ok := archiver.ArchivedPath(date)
+		if !ok {
+			report.Failed = append(report.Failed, Failure{Date: date, Err: fmt.Errorf("board was not archived to %s", path)})
+			continue
+		}
+		slog.Debug("backfilled board", "date", date, "source", imported.Source, "path", path)
+		report.Imported = append(report.Imported, date)
+		if imported.Source != "" {
+			report.Sources[date] = imported.Source
+		}
+	}
+	return report, nil
+}
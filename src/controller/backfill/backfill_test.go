@@ -0,0 +1,109 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azhu2/bongo/src/config/dates"
+	"github.com/azhu2/bongo/src/gateway/gameimporter"
+)
+
+// memoryArchive archives boards in a map. Dates in missing fail to import.
+type memoryArchive struct {
+	boards  map[string]string
+	missing map[string]bool
+	calls   []string
+}
+
+func (m *memoryArchive) ImportBoard(_ context.Context, date string) (string, error) {
+	m.calls = append(m.calls, date)
+	if m.missing[date] {
+		return "", errors.New("no board")
+	}
+	m.boards[date] = "board " + date
+	return m.boards[date], nil
+}
+
+func (m *memoryArchive) ImportSourcedBoard(ctx context.Context, date string) (*gameimporter.Import, error) {
+	board, err := m.ImportBoard(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	return &gameimporter.Import{Board: board, Source: "memory", Extension: ".txt"}, nil
+}
+
+func (m *memoryArchive) ArchivedPath(date string) (string, bool) {
+	_, ok := m.boards[date]
+	return date + ".txt", ok
+}
+
+type unarchived struct{}
+
+func (unarchived) ImportBoard(_ context.Context, _ string) (string, error) {
+	return "", nil
+}
+
+func day(t *testing.T, date string) time.Time {
+	parsed, err := time.Parse(dates.Format, date)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestBackfill(t *testing.T) {
+	ctx := context.Background()
+	archive := &memoryArchive{
+		boards:  map[string]string{"2024-12-30": "board"},
+		missing: map[string]bool{"2025-01-01": true},
+	}
+	result, err := New(Params{Importer: archive, Config: Config{Interval: 10 * time.Millisecond}})
+	require.NoError(t, err)
+
+	start := time.Now()
+	report, err := result.Controller.Backfill(ctx, day(t, "2024-12-29"), day(t, "2025-01-02"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024-12-29", "2024-12-31", "2025-01-02"}, report.Imported)
+	assert.Equal(t, map[string]string{"2024-12-29": "memory", "2024-12-31": "memory", "2025-01-02": "memory"}, report.Sources)
+	assert.Equal(t, []string{"2024-12-30"}, report.Skipped)
+	require.Len(t, report.Failed, 1)
+	assert.Equal(t, "2025-01-01", report.Failed[0].Date)
+	// Archived dates aren't imported or waited for
+	assert.Equal(t, []string{"2024-12-29", "2024-12-31", "2025-01-01", "2025-01-02"}, archive.calls)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	// Running again only retries what failed
+	archive.calls = nil
+	report, err = result.Controller.Backfill(ctx, day(t, "2024-12-29"), day(t, "2025-01-02"))
+	require.NoError(t, err)
+	assert.Empty(t, report.Imported)
+	assert.Equal(t, []string{"2025-01-01"}, archive.calls)
+}
+
+func TestBackfill_Invalid(t *testing.T) {
+	ctx := context.Background()
+	result, err := New(Params{Importer: unarchived{}})
+	require.NoError(t, err)
+	_, err = result.Controller.Backfill(ctx, day(t, "2024-12-23"), day(t, "2024-12-24"))
+	assert.ErrorIs(t, err, ErrNoArchive)
+
+	result, err = New(Params{Importer: &memoryArchive{boards: map[string]string{}}})
+	require.NoError(t, err)
+	_, err = result.Controller.Backfill(ctx, day(t, "2024-12-24"), day(t, "2024-12-23"))
+	assert.Error(t, err)
+}
+
+func TestBackfill_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	archive := &memoryArchive{boards: map[string]string{}}
+	result, err := New(Params{Importer: archive, Config: Config{Interval: time.Hour}})
+	require.NoError(t, err)
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	report, err := result.Controller.Backfill(ctx, day(t, "2024-12-23"), day(t, "2024-12-24"))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"2024-12-23"}, report.Imported)
+}
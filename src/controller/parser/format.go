@@ -0,0 +1,271 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/azhu2/bongo/src/entity"
+)
+
+type Format string
+
+const (
+	// FormatText is the Puzzmo board format
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+var Formats = []Format{FormatText, FormatJSON, FormatYAML}
+
+func ParseFormat(format string) (Format, error) {
+	switch strings.ToLower(format) {
+	case "", "text", "txt":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unknown format: %s", format)
+}
+
+// boardDocument is the JSON/YAML schema for an entity.Board.
+//
+// Coordinates are [row, col] with (0,0) as the top left, the same as the solver uses.
+// The Puzzmo text format writes (x,y)/(col,row) with (0,0) as the bottom left,
+// so (x,y) there is [size-1-y, x] here.
+type boardDocument struct {
+	// Rows and Cols default to the size of the multiplier grid, or 5x5 without one
+	Rows int `json:"rows,omitempty" yaml:"rows,omitempty"`
+	Cols int `json:"cols,omitempty" yaml:"cols,omitempty"`
+	// Tiles maps each letter to its value and how many are available
+	Tiles map[string]tileDocument `json:"tiles" yaml:"tiles"`
+	// Multipliers is the grid of per-cell multipliers, row by row. Omit for all 1s.
+	Multipliers [][]int `json:"multipliers,omitempty" yaml:"multipliers,flow,omitempty"`
+	// BonusWord is the [row, col] of each bonus word letter, in reading order
+	BonusWord [][]int `json:"bonusWord" yaml:"bonusWord,flow"`
+}
+
+type tileDocument struct {
+	Value int `json:"value" yaml:"value"`
+	Count int `json:"count" yaml:"count"`
+}
+
+// solutionDocument is the JSON/YAML schema for an entity.Solution.
+// Rows are written top to bottom, with spaces for empty cells. Letters are read in either case.
+type solutionDocument struct {
+	Rows []string `json:"rows" yaml:"rows"`
+	// Wildcards is the [row, col] of each cell played with the wildcard tile
+	Wildcards [][]int `json:"wildcards,omitempty" yaml:"wildcards,flow,omitempty"`
+}
+
+// yamlKeyRegex matches a top-level boardDocument key, which the text format never starts a line with
+var yamlKeyRegex = regexp.MustCompile(`(?m)^(rows|cols|tiles|multipliers|bonusWord):`)
+
+// DetectFormat sniffs board data. Anything that isn't JSON and has none of the YAML schema's keys
+// is read as text, so a malformed text board reports what's wrong with it as text.
+func DetectFormat(data string) Format {
+	if strings.HasPrefix(strings.TrimSpace(data), "{") {
+		return FormatJSON
+	}
+	if yamlKeyRegex.MatchString(data) {
+		return FormatYAML
+	}
+	return FormatText
+}
+
+func decodeBoard(data string, format Format) (*entity.Board, error) {
+	var doc boardDocument
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(strings.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("unable to decode json board %w", err)
+		}
+	case FormatYAML:
+		decoder := yaml.NewDecoder(strings.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("unable to decode yaml board %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported board format: %s", format)
+	}
+
+	size := entity.DefaultSize
+	if len(doc.Multipliers) > 0 {
+		size = entity.Size{Rows: len(doc.Multipliers), Cols: len(doc.Multipliers[0])}
+	}
+	if doc.Rows != 0 {
+		size.Rows = doc.Rows
+	}
+	if doc.Cols != 0 {
+		size.Cols = doc.Cols
+	}
+	if size.Rows < 1 || size.Cols < 1 {
+		return nil, fmt.Errorf("unexpected board size: %dx%d", size.Cols, size.Rows)
+	}
+
+	board := entity.Board{
+		Size:  size,
+		Tiles: make(map[rune]entity.Tile, len(doc.Tiles)),
+	}
+
+	tileCount := 0
+	for letter, tile := range doc.Tiles {
+		runes := []rune(strings.ToUpper(letter))
+		if len(runes) != 1 {
+			return nil, fmt.Errorf("unable to parse tile letter: %s", letter)
+		}
+		if tile.Count < 1 {
+			return nil, fmt.Errorf("unable to parse tile count: %s %d", letter, tile.Count)
+		}
+		board.Tiles[runes[0]] = entity.Tile{
+			Value: tile.Value,
+			Count: tile.Count,
+		}
+		tileCount += tile.Count
+	}
+	if tileCount < size.Cells() {
+		return nil, fmt.Errorf("incorrect number of tiles found: %d", tileCount)
+	}
+
+	if doc.Multipliers == nil {
+		board.Multipliers = size.EmptyMultipliers()
+	} else {
+		if len(doc.Multipliers) != size.Rows {
+			return nil, fmt.Errorf("unexpected number of multiplier rows: %d", len(doc.Multipliers))
+		}
+		for i, row := range doc.Multipliers {
+			if len(row) != size.Cols {
+				return nil, fmt.Errorf("unexpected number of multipliers in row %d: %d", i, len(row))
+			}
+		}
+		board.Multipliers = doc.Multipliers
+	}
+
+	if len(doc.BonusWord) == 0 {
+		return nil, fmt.Errorf("board has no bonus word")
+	}
+	for _, coord := range doc.BonusWord {
+		if len(coord) != 2 || !size.Contains(coord[0], coord[1]) {
+			return nil, fmt.Errorf("unable to parse bonus word coordinate %v", coord)
+		}
+	}
+	board.BonusWord = doc.BonusWord
+
+	return &board, nil
+}
+
+func encodeBoard(board *entity.Board, format Format) (string, error) {
+	doc := boardDocument{
+		Rows:        board.Size.Rows,
+		Cols:        board.Size.Cols,
+		Tiles:       make(map[string]tileDocument, len(board.Tiles)),
+		Multipliers: board.Multipliers,
+		BonusWord:   board.BonusWord,
+	}
+	for letter, tile := range board.Tiles {
+		doc.Tiles[string(letter)] = tileDocument{
+			Value: tile.Value,
+			Count: tile.Count,
+		}
+	}
+	return encode(doc, format)
+}
+
+func decodeSolution(data string, format Format) (entity.Solution, error) {
+	var doc solutionDocument
+	switch format {
+	case FormatText:
+		// Same as entity.Solution.String(), optionally split over lines instead of pipes.
+		// Wildcards are marked with entity.WildcardMarker.
+		for _, row := range strings.FieldsFunc(strings.TrimRight(data, "\n"), func(r rune) bool {
+			return r == '|' || r == '\n'
+		}) {
+			letters, wildcards := entity.ParseDisplayRow(row)
+			for col, isWildcard := range wildcards {
+				if isWildcard {
+					doc.Wildcards = append(doc.Wildcards, []int{len(doc.Rows), col})
+				}
+			}
+			doc.Rows = append(doc.Rows, string(letters))
+		}
+	case FormatJSON:
+		decoder := json.NewDecoder(strings.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&doc); err != nil {
+			return entity.Solution{}, fmt.Errorf("unable to decode json solution %w", err)
+		}
+	case FormatYAML:
+		decoder := yaml.NewDecoder(strings.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&doc); err != nil {
+			return entity.Solution{}, fmt.Errorf("unable to decode yaml solution %w", err)
+		}
+	default:
+		return entity.Solution{}, fmt.Errorf("unsupported solution format: %s", format)
+	}
+
+	if len(doc.Rows) == 0 {
+		return entity.Solution{}, fmt.Errorf("solution has no rows")
+	}
+	// Trailing blanks may have been trimmed, so the widest row sets the size
+	size := entity.Size{Rows: len(doc.Rows)}
+	for _, row := range doc.Rows {
+		size.Cols = max(size.Cols, len([]rune(row)))
+	}
+	solution := entity.EmptySolution(size)
+	for i, row := range doc.Rows {
+		solution.SetRow(i, []rune(row))
+	}
+	for _, coord := range doc.Wildcards {
+		if len(coord) != 2 || !size.Contains(coord[0], coord[1]) || solution.Get(coord[0], coord[1]) == ' ' {
+			return entity.Solution{}, fmt.Errorf("unable to parse wildcard coordinate %v", coord)
+		}
+		solution.SetWildcard(coord[0], coord[1], true)
+	}
+	return solution, nil
+}
+
+func encodeSolution(solution entity.Solution, format Format) (string, error) {
+	if format == FormatText {
+		return solution.String(), nil
+	}
+	doc := solutionDocument{}
+	for _, row := range solution.Rows() {
+		doc.Rows = append(doc.Rows, string(row))
+	}
+	for _, cell := range solution.WildcardCells() {
+		doc.Wildcards = append(doc.Wildcards, []int{cell.Row, cell.Col})
+	}
+	return encode(doc, format)
+}
+
+func encode(doc any, format Format) (string, error) {
+	switch format {
+	case FormatJSON:
+		raw, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("unable to encode json %w", err)
+		}
+		return string(raw) + "\n", nil
+	case FormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return "", fmt.Errorf("unable to encode yaml %w", err)
+		}
+		encoder.Close()
+		return buf.String(), nil
+	}
+	return "", fmt.Errorf("unsupported format: %s", format)
+}
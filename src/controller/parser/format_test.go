@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azhu2/bongo/src/entity"
+	"github.com/azhu2/bongo/testdata"
+)
+
+func TestSerializeBoard_Formats(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		for _, tt := range testdata.TestData {
+			t.Run(string(format)+"/"+tt.Date, func(t *testing.T) {
+				ctx := context.Background()
+				result, _ := New(Params{Rules: entity.DefaultRules()})
+				c := result.Controller
+
+				serialized, err := c.SerializeBoard(ctx, tt.Board, format)
+				require.NoError(t, err)
+				assert.Equal(t, format, DetectFormat(serialized))
+
+				board, err := c.ParseBoard(ctx, serialized)
+				require.NoError(t, err)
+				assert.Equal(t, tt.Board.Tiles, board.Tiles, "tiles should match")
+				assert.Equal(t, tt.Board.Multipliers, board.Multipliers, "multipliers should match")
+				assert.Equal(t, tt.Board.BonusWord, board.BonusWord, "bonus word should match")
+			})
+		}
+	}
+}
+
+func TestParseBoard_JSON(t *testing.T) {
+	result, _ := New(Params{Rules: entity.DefaultRules()})
+	c := result.Controller
+
+	// Same board as testdata/2024-12-24.txt, with coordinates converted to (row,col)
+	board, err := c.ParseBoard(context.Background(), `{
+		"tiles": {
+			"G": {"value": 45, "count": 2}, "P": {"value": 35, "count": 1}, "C": {"value": 35, "count": 3},
+			"H": {"value": 50, "count": 1}, "Y": {"value": 35, "count": 1}, "N": {"value": 20, "count": 2},
+			"T": {"value": 9, "count": 2}, "I": {"value": 10, "count": 2}, "L": {"value": 10, "count": 1},
+			"O": {"value": 7, "count": 2}, "R": {"value": 7, "count": 1}, "A": {"value": 5, "count": 6},
+			"S": {"value": 5, "count": 1}
+		},
+		"multipliers": [[1,1,1,1,1], [1,1,1,1,1], [1,1,3,1,1], [1,1,1,1,2], [1,1,1,1,2]],
+		"bonusWord": [[0,1], [1,1], [2,1], [3,1]]
+	}`)
+	require.NoError(t, err)
+	expected := testdata.TestData[1].Board
+	assert.Equal(t, expected.Tiles, board.Tiles)
+	assert.Equal(t, expected.Multipliers, board.Multipliers)
+	assert.Equal(t, expected.BonusWord, board.BonusWord)
+}
+
+func TestParseBoard_YAMLDefaultMultipliers(t *testing.T) {
+	result, _ := New(Params{Rules: entity.DefaultRules()})
+	c := result.Controller
+
+	board, err := c.ParseBoard(context.Background(), `
+tiles:
+  A: {value: 5, count: 10}
+  E: {value: 5, count: 10}
+  N: {value: 20, count: 5}
+bonusWord: [[0, 0], [1, 1], [2, 2]]
+`)
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 1, 1, 1, 1}, {1, 1, 1, 1, 1}, {1, 1, 1, 1, 1}, {1, 1, 1, 1, 1}, {1, 1, 1, 1, 1}}, board.Multipliers)
+	assert.Equal(t, 20, board.Tiles['N'].Value)
+}
+
+func TestParseBoard_InvalidDocument(t *testing.T) {
+	result, _ := New(Params{Rules: entity.DefaultRules()})
+	c := result.Controller
+
+	_, err := c.ParseBoard(context.Background(), `{"tiles": {"A": {"value": 5, "count": 25}}, "bonusWord": [[0, 5]]}`)
+	assert.Error(t, err, "bonus word out of bounds")
+
+	_, err = c.ParseBoard(context.Background(), `{"tiles": {"A": {"value": 5, "count": 2}}, "bonusWord": [[0, 0]]}`)
+	assert.Error(t, err, "not enough tiles")
+}
+
+func TestSerializeSolution_Formats(t *testing.T) {
+	for _, format := range Formats {
+		for _, tt := range testdata.TestData {
+			t.Run(string(format)+"/"+tt.Date, func(t *testing.T) {
+				ctx := context.Background()
+				result, _ := New(Params{Rules: entity.DefaultRules()})
+				c := result.Controller
+
+				serialized, err := c.SerializeSolution(ctx, tt.Solution, format)
+				require.NoError(t, err)
+				solution, err := c.ParseSolution(ctx, serialized, format)
+				require.NoError(t, err)
+				assert.Equal(t, tt.Solution, solution)
+			})
+		}
+	}
+}
+
+func TestSerializeSolution_Wildcards(t *testing.T) {
+	ctx := context.Background()
+	result, _ := New(Params{Rules: entity.DefaultRules()})
+	c := result.Controller
+	solution := entity.NewSolution(entity.DefaultSize, "SW*ORNSHAMEPLANESEEPSREEDY")
+
+	text, err := c.SerializeSolution(ctx, solution, FormatText)
+	require.NoError(t, err)
+	assert.Equal(t, "SW*ORN|SHAME|PLANE|SEEPS|REEDY|", text)
+
+	json, err := c.SerializeSolution(ctx, solution, FormatJSON)
+	require.NoError(t, err)
+	assert.Contains(t, json, `"SWORN"`)
+
+	for _, format := range Formats {
+		serialized, err := c.SerializeSolution(ctx, solution, format)
+		require.NoError(t, err)
+		parsed, err := c.ParseSolution(ctx, serialized, format)
+		require.NoError(t, err)
+		assert.Equal(t, []entity.Cell{{Row: 0, Col: 2}}, parsed.WildcardCells(), string(format))
+		assert.Equal(t, 'O', parsed.Get(0, 2))
+	}
+}
+
+func TestParseBoard_MalformedText(t *testing.T) {
+	result, _ := New(Params{Rules: entity.DefaultRules()})
+	c := result.Controller
+
+	lines := strings.Split(extractBoardData(t, "2024-12-23"), "\n")
+	lines[1] = "5 by 5"
+
+	_, err := c.ParseBoard(context.Background(), strings.Join(lines, "\n"))
+	assert.ErrorContains(t, err, "unable to parse board size")
+
+	_, err = c.ParseBoard(context.Background(), "2\n5x5\n")
+	assert.ErrorContains(t, err, "text board is missing lines")
+}
+
+func TestParseSolution_UnknownFields(t *testing.T) {
+	result, _ := New(Params{Rules: entity.DefaultRules()})
+	c := result.Controller
+
+	_, err := c.ParseSolution(context.Background(), `{"rows": ["CAT"], "wildcard": [[0, 0]]}`, FormatJSON)
+	assert.Error(t, err)
+
+	_, err = c.ParseSolution(context.Background(), "rows: [CAT]\nwildcard: [[0, 0]]\n", FormatYAML)
+	assert.Error(t, err)
+}
+
+func TestParseSolution_CaseInsensitive(t *testing.T) {
+	ctx := context.Background()
+	result, _ := New(Params{Rules: entity.DefaultRules()})
+	c := result.Controller
+	want := entity.NewSolution(entity.Size{Rows: 2, Cols: 3}, "CAT*TAB")
+
+	for format, data := range map[Format]string{
+		FormatText: "cat|*tab|",
+		FormatJSON: `{"rows": ["cat", "tab"], "wildcards": [[1, 0]]}`,
+		FormatYAML: "rows: [cat, tab]\nwildcards: [[1, 0]]\n",
+	} {
+		solution, err := c.ParseSolution(ctx, data, format)
+		require.NoError(t, err, string(format))
+		assert.Equal(t, want, solution, string(format))
+	}
+}
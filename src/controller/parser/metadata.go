@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/azhu2/bongo/src/entity"
+)
+
+const metadataHeader = "METADATA:"
+
+var (
+	metadataSolutionRegex = regexp.MustCompile(`^(Best|Worst): \[(.*)\](?: \(\+ (\w+)\))? - (\d+)$`) // Best: ['PEAS', 'SWORN'] (+ WHEN) - 1089
+	scoreBandRegex        = regexp.MustCompile(`^(\d+)-(\d+): (.*)$`)                                // 300-400: SWAPS, SWAMP
+	quotedWordRegex       = regexp.MustCompile(`'(\w+)'`)
+)
+
+// ErrNoMetadata is returned for boards without a METADATA section, like the JSON and YAML formats
+var ErrNoMetadata = errors.New("no metadata")
+
+func (i *parser) ParseMetadata(_ context.Context, boardData string) (*entity.Metadata, error) {
+	_, section, ok := strings.Cut(boardData, metadataHeader)
+	if !ok {
+		return nil, ErrNoMetadata
+	}
+
+	metadata := entity.Metadata{}
+	// Other statistics like solution counts are skipped
+	for _, line := range strings.Split(section, "\n") {
+		line = strings.TrimSpace(line)
+		if match := metadataSolutionRegex.FindStringSubmatch(line); match != nil {
+			score, err := strconv.Atoi(match[4])
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse metadata score: %s", line)
+			}
+			solution := entity.MetadataSolution{
+				BonusWord: match[3],
+				Score:     score,
+			}
+			for _, word := range quotedWordRegex.FindAllStringSubmatch(match[2], -1) {
+				solution.Words = append(solution.Words, word[1])
+			}
+			if match[1] == "Best" {
+				metadata.Best = solution
+			} else {
+				metadata.Worst = solution
+			}
+			continue
+		}
+		if match := scoreBandRegex.FindStringSubmatch(line); match != nil {
+			low, lowErr := strconv.Atoi(match[1])
+			high, highErr := strconv.Atoi(match[2])
+			if lowErr != nil || highErr != nil {
+				return nil, fmt.Errorf("unable to parse score band: %s", line)
+			}
+			band := entity.ScoreBand{Low: low, High: high}
+			for _, word := range strings.Split(match[3], ",") {
+				if word = strings.TrimSpace(word); word != "" {
+					band.Words = append(band.Words, word)
+				}
+			}
+			metadata.ScoreBands = append(metadata.ScoreBands, band)
+		}
+	}
+	return &metadata, nil
+}
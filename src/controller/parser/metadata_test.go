@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azhu2/bongo/src/entity"
+	"github.com/azhu2/bongo/testdata"
+)
+
+func TestParseMetadata(t *testing.T) {
+	ctx := context.Background()
+	result, _ := New(Params{Rules: entity.DefaultRules()})
+	c := result.Controller
+
+	raw, err := os.ReadFile(filepath.Join(testdataDir, "2024-12-23.txt"))
+	require.NoError(t, err)
+	metadata, err := c.ParseMetadata(ctx, string(raw))
+	require.NoError(t, err)
+
+	assert.Equal(t, entity.MetadataSolution{
+		Words:     []string{"PEAS", "PEEN", "REEDY", "SHES", "SWORN"},
+		BonusWord: "WHEN",
+		Score:     1089,
+	}, metadata.Best)
+	assert.Equal(t, entity.MetadataSolution{
+		Words: []string{"HAE", "LSD", "PERE", "RENY", "SESSA"},
+		Score: 314,
+	}, metadata.Worst)
+	require.Len(t, metadata.ScoreBands, 3)
+	assert.Equal(t, entity.ScoreBand{
+		Low:   300,
+		High:  400,
+		Words: []string{"SWAPS", "SWAMP", "SWORN", "SWEEP", "SWAYS", "SWARM", "SWAP"},
+	}, metadata.ScoreBands[0])
+	assert.Equal(t, 175, metadata.ScoreBands[2].Low)
+	assert.Contains(t, metadata.Words(), "WHEN")
+}
+
+func TestParseMetadata_None(t *testing.T) {
+	ctx := context.Background()
+	result, _ := New(Params{Rules: entity.DefaultRules()})
+	c := result.Controller
+
+	serialized, err := c.SerializeBoard(ctx, testdata.TestData[0].Board, FormatJSON)
+	require.NoError(t, err)
+	_, err = c.ParseMetadata(ctx, serialized)
+	assert.ErrorIs(t, err, ErrNoMetadata)
+}
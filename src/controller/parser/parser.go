@@ -14,10 +14,10 @@ import (
 )
 
 var (
-	boardSizeRegex  = regexp.MustCompile(`(\d)x(\d)`)
-	coordinateRegex = regexp.MustCompile(`\((\d),(\d)\)`)        // (1,4)
-	multiplierRegex = regexp.MustCompile(`(\((\d),(\d)\))x(\d)`) // (1,4)x2
-	tileRegex       = regexp.MustCompile(`(\w)x(\d):(\d+)`)      // Gx2:45(10) - final parenthetical part is ignored
+	boardSizeRegex  = regexp.MustCompile(`(\d+)x(\d+)`)            // 5x5 - columns x rows
+	coordinateRegex = regexp.MustCompile(`\((\d+),(\d+)\)`)        // (1,4)
+	multiplierRegex = regexp.MustCompile(`(\((\d+),(\d+)\))x(\d)`) // (1,4)x2
+	tileRegex       = regexp.MustCompile(`(\w)x(\d):(\d+)`)        // Gx2:45(10) - final parenthetical part is ignored
 )
 
 var Module = fx.Module("parser",
@@ -25,7 +25,19 @@ var Module = fx.Module("parser",
 )
 
 type Controller interface {
+	// ParseBoard reads a board in any supported format, detecting which one from the data
 	ParseBoard(ctx context.Context, boardData string) (*entity.Board, error)
+	SerializeBoard(ctx context.Context, board *entity.Board, format Format) (string, error)
+	ParseSolution(ctx context.Context, solutionData string, format Format) (entity.Solution, error)
+	SerializeSolution(ctx context.Context, solution entity.Solution, format Format) (string, error)
+	// ParseMetadata reads the statistics after a text board, or returns ErrNoMetadata
+	ParseMetadata(ctx context.Context, boardData string) (*entity.Metadata, error)
+}
+
+type Params struct {
+	fx.In
+
+	Rules entity.Rules
 }
 
 type Result struct {
@@ -34,30 +46,66 @@ type Result struct {
 	Controller
 }
 
-type parser struct{}
+type parser struct {
+	rules entity.Rules
+}
 
-func New() (Result, error) {
+func New(p Params) (Result, error) {
 	return Result{
-		Controller: &parser{},
+		Controller: &parser{
+			rules: p.Rules,
+		},
 	}, nil
 }
 
 func (i *parser) ParseBoard(ctx context.Context, boardData string) (*entity.Board, error) {
+	var board *entity.Board
+	var err error
+	format := DetectFormat(boardData)
+	if format == FormatText {
+		board, err = i.parseTextBoard(ctx, boardData)
+	} else {
+		board, err = decodeBoard(boardData, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !i.rules.IsPlayableLength(len(board.BonusWord)) {
+		return nil, fmt.Errorf("bonus word length %d not allowed by rules", len(board.BonusWord))
+	}
+
+	slog.Debug("parsed board", "format", format)
+	return board, nil
+}
+
+func (i *parser) ParseSolution(ctx context.Context, solutionData string, format Format) (entity.Solution, error) {
+	return decodeSolution(solutionData, format)
+}
+
+func (i *parser) SerializeSolution(ctx context.Context, solution entity.Solution, format Format) (string, error) {
+	return encodeSolution(solution, format)
+}
+
+func (i *parser) parseTextBoard(_ context.Context, boardData string) (*entity.Board, error) {
 	board := entity.Board{}
 	lines := strings.Split(boardData, "\n")
+	// Header, size, seed words, blank, par, bonus word, multipliers and at least one tile
+	if len(lines) < 8 {
+		return nil, fmt.Errorf("text board is missing lines: found %d", len(lines))
+	}
 
 	idx := 0
 
 	// Ignore line 0
 	idx++
 
-	// Double-check board size
-	sizeMatch := boardSizeRegex.FindStringSubmatch(lines[idx])
-	if len(sizeMatch) == 0 ||
-		sizeMatch[1] != strconv.Itoa(entity.BoardSize) ||
-		sizeMatch[2] != strconv.Itoa(entity.BoardSize) {
-		return nil, fmt.Errorf("unexpected board size: %s", lines[idx])
+	// Parse board size
+	size, err := parseBoardSize(strings.TrimSpace(lines[idx]))
+	if err != nil {
+		return nil, err
 	}
+	board.Size = size
 	idx++
 
 	// Skip seed words
@@ -70,7 +118,7 @@ func (i *parser) ParseBoard(ctx context.Context, boardData string) (*entity.Boar
 	idx++
 
 	// Parse bonus word
-	bonus, err := parseBonusWord(strings.TrimSpace(lines[idx]))
+	bonus, err := parseBonusWord(size, strings.TrimSpace(lines[idx]))
 	if err != nil {
 		return nil, err
 	}
@@ -78,7 +126,7 @@ func (i *parser) ParseBoard(ctx context.Context, boardData string) (*entity.Boar
 	idx++
 
 	// Parse multipliers
-	multipliers, err := parseMultipliers(strings.TrimSpace(lines[idx]))
+	multipliers, err := parseMultipliers(size, strings.TrimSpace(lines[idx]))
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +136,7 @@ func (i *parser) ParseBoard(ctx context.Context, boardData string) (*entity.Boar
 	// Parse tiles
 	board.Tiles = make(map[rune]entity.Tile)
 	tileCount := 0
-	for lines[idx] != "" {
+	for idx < len(lines) && lines[idx] != "" {
 		letter, tile, err := parseTile(strings.TrimSpace(lines[idx]))
 		if err != nil {
 			return nil, err
@@ -106,16 +154,33 @@ func (i *parser) ParseBoard(ctx context.Context, boardData string) (*entity.Boar
 		tileCount += tile.Count
 		idx++
 	}
-	if tileCount < entity.BoardSize*entity.BoardSize {
+	if tileCount < size.Cells() {
 		return nil, fmt.Errorf("incorrect number of tiles found: %d", len(board.Tiles))
 	}
 
-	slog.Debug("parsed board")
-
 	return &board, nil
 }
 
-func parseBonusWord(line string) ([][]int, error) {
+func parseBoardSize(line string) (entity.Size, error) {
+	match := boardSizeRegex.FindStringSubmatch(line)
+	if len(match) == 0 {
+		return entity.Size{}, fmt.Errorf("unable to parse board size: %s", line)
+	}
+	cols, err := strconv.Atoi(match[1])
+	if err != nil {
+		return entity.Size{}, fmt.Errorf("unable to parse board size: %s %w", line, err)
+	}
+	rows, err := strconv.Atoi(match[2])
+	if err != nil {
+		return entity.Size{}, fmt.Errorf("unable to parse board size: %s %w", line, err)
+	}
+	if rows < 1 || cols < 1 {
+		return entity.Size{}, fmt.Errorf("unexpected board size: %s", line)
+	}
+	return entity.Size{Rows: rows, Cols: cols}, nil
+}
+
+func parseBonusWord(size entity.Size, line string) ([][]int, error) {
 	matches := coordinateRegex.FindAllStringSubmatch(line, -1)
 	if len(matches) == 0 {
 		return nil, fmt.Errorf("unable to parse bonus word coordinates %s", line)
@@ -123,7 +188,7 @@ func parseBonusWord(line string) ([][]int, error) {
 
 	bonus := make([][]int, len(matches))
 	for i, match := range matches {
-		x, y, err := parseCoordinate(match[0])
+		x, y, err := parseCoordinate(size, match[0])
 		if err != nil {
 			return nil, fmt.Errorf("unable to parse bonus word coordinate %w", err)
 		}
@@ -132,12 +197,9 @@ func parseBonusWord(line string) ([][]int, error) {
 	return bonus, nil
 }
 
-func parseMultipliers(line string) ([][]int, error) {
+func parseMultipliers(size entity.Size, line string) ([][]int, error) {
 	// Default to 1s everywhere
-	multipliers := make([][]int, entity.BoardSize)
-	for i := 0; i < entity.BoardSize; i++ {
-		multipliers[i] = []int{1, 1, 1, 1, 1}
-	}
+	multipliers := size.EmptyMultipliers()
 
 	matches := multiplierRegex.FindAllStringSubmatch(line, -1)
 	if len(matches) == 0 {
@@ -145,7 +207,7 @@ func parseMultipliers(line string) ([][]int, error) {
 	}
 	for _, match := range matches {
 		coord := match[1]
-		x, y, err := parseCoordinate(coord)
+		x, y, err := parseCoordinate(size, coord)
 		if err != nil {
 			return nil, fmt.Errorf("unable to parse multiplier coordinate: %s %w", coord, err)
 		}
@@ -182,7 +244,7 @@ func parseTile(line string) (rune, entity.Tile, error) {
 // Flip the coordinate grid
 // Input data is (x,y)/(col,row) with (0,0) as bottom left
 // We'll flip to (row,col) with (0,0) as top left
-func parseCoordinate(coord string) (int, int, error) {
+func parseCoordinate(size entity.Size, coord string) (int, int, error) {
 	match := coordinateRegex.FindStringSubmatch(coord)
 	if len(match) == 0 {
 		return 0, 0, fmt.Errorf("unable to parse coordinate: %s", coord)
@@ -195,5 +257,9 @@ func parseCoordinate(coord string) (int, int, error) {
 	if perr != nil {
 		return 0, 0, fmt.Errorf("unable to parse coordinate: %s %w", coord, perr)
 	}
-	return entity.BoardSize - y - 1, x, nil
+	row, col := size.Rows-y-1, x
+	if !size.Contains(row, col) {
+		return 0, 0, fmt.Errorf("coordinate out of bounds: %s", coord)
+	}
+	return row, col, nil
 }
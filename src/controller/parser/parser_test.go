@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/azhu2/bongo/src/entity"
 	"github.com/azhu2/bongo/src/gateway/gameimporter"
 	"github.com/azhu2/bongo/testdata"
 )
@@ -16,11 +17,12 @@ func TestParseBoard(t *testing.T) {
 		t.Run(tt.Date, func(t *testing.T) {
 			data := extractBoardData(t, tt.Date)
 
-			result, _ := New()
+			result, _ := New(Params{Rules: entity.DefaultRules()})
 			c := result.Controller
 			board, err := c.ParseBoard(context.Background(), data)
 			assert.NoError(t, err)
 			assert.NotNil(t, board)
+			assert.Equal(t, tt.Board.Size, board.Size, "size should match")
 			assert.Equal(t, tt.Board.Tiles, board.Tiles, "tiles should match")
 			assert.Equal(t, tt.Board.Multipliers, board.Multipliers, "multipliers should match")
 			assert.Equal(t, tt.Board.BonusWord, board.BonusWord, "bonus word should match")
@@ -28,8 +30,21 @@ func TestParseBoard(t *testing.T) {
 	}
 }
 
+func TestParseBoard_NonSquare(t *testing.T) {
+	result, _ := New(Params{Rules: entity.DefaultRules()})
+	c := result.Controller
+	board, err := c.ParseBoard(context.Background(), "1\n4x3\nSEED\n\n100\n(0,2) (1,1) (2,0)\n(3,0)x2\nAx6:5\nEx6:5\n\n")
+	require.NoError(t, err)
+	assert.Equal(t, entity.Size{Rows: 3, Cols: 4}, board.Size)
+	assert.Equal(t, [][]int{{0, 0}, {1, 1}, {2, 2}}, board.BonusWord)
+	assert.Equal(t, [][]int{{1, 1, 1, 1}, {1, 1, 1, 1}, {1, 1, 1, 2}}, board.Multipliers)
+
+	_, err = c.ParseBoard(context.Background(), "1\n4x3\nSEED\n\n100\n(0,3)\n(3,0)x2\nAx6:5\nEx6:5\n\n")
+	assert.Error(t, err, "bonus word outside of board")
+}
+
 func extractBoardData(t *testing.T, date string) string {
-	importer, err := gameimporter.NewFile(gameimporter.Params{})
+	importer, err := gameimporter.NewFile(gameimporter.Params{File: gameimporter.FileConfig{Dir: testdataDir}})
 	require.NoError(t, err)
 	data, err := importer.Gateway.ImportBoard(context.Background(), date)
 	require.NoError(t, err)
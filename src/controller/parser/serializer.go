@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azhu2/bongo/src/entity"
+)
+
+const (
+	// Leading line of the Puzzmo format. It is not parsed, so mirror what archived boards contain.
+	formatVersion = "2"
+)
+
+func (i *parser) SerializeBoard(ctx context.Context, board *entity.Board, format Format) (string, error) {
+	if format != FormatText {
+		return encodeBoard(board, format)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(formatVersion + "\n")
+	fmt.Fprintf(&sb, "%dx%d\n", board.Size.Cols, board.Size.Rows)
+
+	// Seed words and par are skipped by the parser and not kept on the board
+	sb.WriteString("\n")
+	sb.WriteString("\n")
+	sb.WriteString("0\n")
+
+	bonus, err := serializeBonusWord(board.Size, board.BonusWord)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(bonus + "\n")
+
+	multipliers, err := serializeMultipliers(board.Size, board.Multipliers)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(multipliers + "\n")
+
+	tiles, err := serializeTiles(board.Tiles)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range tiles {
+		sb.WriteString(line + "\n")
+	}
+	// Tile list is terminated by an empty line
+	sb.WriteString("\n")
+
+	return sb.String(), nil
+}
+
+func serializeBonusWord(size entity.Size, bonus [][]int) (string, error) {
+	if len(bonus) == 0 {
+		return "", fmt.Errorf("board has no bonus word")
+	}
+	coords := make([]string, len(bonus))
+	for i, coord := range bonus {
+		formatted, err := formatCoordinate(size, coord[0], coord[1])
+		if err != nil {
+			return "", fmt.Errorf("unable to serialize bonus word coordinate %w", err)
+		}
+		coords[i] = formatted
+	}
+	return strings.Join(coords, " "), nil
+}
+
+func serializeMultipliers(size entity.Size, multipliers [][]int) (string, error) {
+	entries := []string{}
+	for row, rowData := range multipliers {
+		for col, multiplier := range rowData {
+			if multiplier == 1 {
+				continue
+			}
+			// multiplierRegex only reads a single digit
+			if multiplier < 0 || multiplier > 9 {
+				return "", fmt.Errorf("unable to serialize multiplier: %d", multiplier)
+			}
+			coord, err := formatCoordinate(size, row, col)
+			if err != nil {
+				return "", fmt.Errorf("unable to serialize multiplier coordinate %w", err)
+			}
+			entries = append(entries, fmt.Sprintf("%sx%d", coord, multiplier))
+		}
+	}
+	if len(entries) == 0 {
+		// The parser requires at least one multiplier, so write out an explicit no-op
+		coord, _ := formatCoordinate(size, 0, 0)
+		entries = append(entries, coord+"x1")
+	}
+	return strings.Join(entries, " "), nil
+}
+
+// Highest value tiles first, like the archived boards. tileRegex only reads a single digit count,
+// so larger counts are split across lines, which the parser adds back together.
+func serializeTiles(tiles map[rune]entity.Tile) ([]string, error) {
+	letters := make([]rune, 0, len(tiles))
+	for letter := range tiles {
+		letters = append(letters, letter)
+	}
+	slices.SortFunc(letters, func(x, y rune) int {
+		if diff := tiles[y].Value - tiles[x].Value; diff != 0 {
+			return diff
+		}
+		return int(x - y)
+	})
+
+	lines := []string{}
+	for _, letter := range letters {
+		tile := tiles[letter]
+		if tile.Count < 1 {
+			return nil, fmt.Errorf("unable to serialize tile count: %c %d", letter, tile.Count)
+		}
+		for count := tile.Count; count > 0; count -= 9 {
+			lines = append(lines, fmt.Sprintf("%cx%d:%d", letter, min(count, 9), tile.Value))
+		}
+	}
+	return lines, nil
+}
+
+// Reverse of parseCoordinate
+// Input is (row,col) with (0,0) as top left
+// Output data is (x,y)/(col,row) with (0,0) as bottom left
+func formatCoordinate(size entity.Size, row, col int) (string, error) {
+	if !size.Contains(row, col) {
+		return "", fmt.Errorf("coordinate out of bounds: (%d,%d)", row, col)
+	}
+	return fmt.Sprintf("(%d,%d)", col, size.Rows-row-1), nil
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azhu2/bongo/src/entity"
+)
+
+const testdataDir = "../../../testdata"
+
+func TestSerializeBoard_RoundTrip(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(testdataDir, "*.txt"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			ctx := context.Background()
+			raw, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			result, _ := New(Params{Rules: entity.DefaultRules()})
+			c := result.Controller
+			board, err := c.ParseBoard(ctx, string(raw))
+			require.NoError(t, err)
+
+			serialized, err := c.SerializeBoard(ctx, board, FormatText)
+			require.NoError(t, err)
+			reparsed, err := c.ParseBoard(ctx, serialized)
+			require.NoError(t, err)
+			assert.Equal(t, board.Tiles, reparsed.Tiles, "tiles should match")
+			assert.Equal(t, board.Multipliers, reparsed.Multipliers, "multipliers should match")
+			assert.Equal(t, board.BonusWord, reparsed.BonusWord, "bonus word should match")
+
+			// Bonus word order is significant, so it should come back verbatim
+			originalLines := strings.Split(string(raw), "\n")
+			serializedLines := strings.Split(serialized, "\n")
+			assert.Equal(t, strings.TrimSpace(originalLines[5]), serializedLines[5], "bonus word line should match")
+
+			reserialized, err := c.SerializeBoard(ctx, reparsed, FormatText)
+			require.NoError(t, err)
+			assert.Equal(t, serialized, reserialized, "serialization should be stable")
+		})
+	}
+}
+
+func TestSerializeBoard_Limits(t *testing.T) {
+	ctx := context.Background()
+	result, _ := New(Params{Rules: entity.DefaultRules()})
+	c := result.Controller
+	board := &entity.Board{
+		Size:        entity.Size{Rows: 2, Cols: 6},
+		Tiles:       map[rune]entity.Tile{'E': {Value: 1, Count: 11}, 'Q': {Value: 10, Count: 1}},
+		Multipliers: [][]int{{1, 1, 1, 1, 1, 1}, {1, 1, 9, 1, 1, 1}},
+		BonusWord:   [][]int{{0, 0}, {0, 1}, {0, 2}},
+	}
+
+	// Counts over 9 are split across lines and added back up
+	serialized, err := c.SerializeBoard(ctx, board, FormatText)
+	require.NoError(t, err)
+	assert.Contains(t, serialized, "Ex9:1\nEx2:1\n")
+	reparsed, err := c.ParseBoard(ctx, serialized)
+	require.NoError(t, err)
+	assert.Equal(t, board.Tiles, reparsed.Tiles)
+	assert.Equal(t, board.Multipliers, reparsed.Multipliers)
+
+	// Multipliers over 9 can't be read back
+	board.Multipliers[1][2] = 10
+	_, err = c.SerializeBoard(ctx, board, FormatText)
+	assert.ErrorContains(t, err, "unable to serialize multiplier: 10")
+}
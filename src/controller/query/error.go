@@ -0,0 +1,21 @@
+package query
+
+import "fmt"
+
+// InvalidQueryError means the query itself can't be answered, as opposed to a failure looking it up
+type InvalidQueryError struct {
+	reason string
+}
+
+func invalidQuery(format string, args ...any) InvalidQueryError {
+	return InvalidQueryError{reason: fmt.Sprintf(format, args...)}
+}
+
+func (e InvalidQueryError) Error() string {
+	return e.reason
+}
+
+func (e InvalidQueryError) Is(target error) bool {
+	_, ok := target.(InvalidQueryError)
+	return ok
+}
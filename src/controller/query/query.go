@@ -0,0 +1,384 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+
+	"go.uber.org/fx"
+
+	"github.com/azhu2/bongo/src/controller/scorer"
+	"github.com/azhu2/bongo/src/entity"
+)
+
+const (
+	// AnyLetter in a pattern matches any single letter
+	AnyLetter = '?'
+	// Blank in a pattern is an empty cell. Only allowed before or after the word.
+	Blank = '_'
+)
+
+var Module = fx.Module("query",
+	fx.Provide(New),
+)
+
+type Controller interface {
+	// Words finds the words that fit a pattern, best scoring first
+	Words(context.Context, Query) ([]Match, error)
+	// Anagrams finds every playable word that can be built from a board's tiles, shortest first
+	Anagrams(ctx context.Context, board *entity.Board, useWildcards bool) []AnagramGroup
+	// Placements scores every buildable word on every row of a board, best first
+	Placements(ctx context.Context, board *entity.Board) ([]Placement, error)
+}
+
+// Query is a pattern like "S?O?N" or "_?AT_", read as one board row
+type Query struct {
+	Pattern string
+	// Tiles limits words to these letters plus the rules' wildcards. Any letters go if nil.
+	Tiles map[rune]int
+	// Board and Row score each word as if it were played alone on that row.
+	// Without a board, matches are sorted alphabetically and have no score.
+	Board *entity.Board
+	Row   int
+}
+
+type Match struct {
+	Word string `json:"word"`
+	// Col is where the word starts in the row
+	Col   int `json:"col"`
+	Score int `json:"score"`
+}
+
+// AnagramGroup is every anagram of one length, highest value first
+type AnagramGroup struct {
+	Length int       `json:"length"`
+	Words  []Anagram `json:"words"`
+}
+
+type Anagram struct {
+	Word string `json:"word"`
+	// Value is the sum of the word's tile values, ignoring multipliers. A wildcard is worth 0.
+	Value    int  `json:"value"`
+	Wildcard bool `json:"wildcard"`
+}
+
+// Placement is where a word scores best on each row of a board, as the only word played
+type Placement struct {
+	Word string `json:"word"`
+	// Rows has the best placement on each row, top to bottom
+	Rows []RowPlacement `json:"rows"`
+	// Best is the highest scoring row
+	Best RowPlacement `json:"best"`
+	// Bonus is whether the word can be laid along the bonus path: it is as long as the path, and
+	// each row the path crosses has a word with the bonus letters on the path's cells. Rows are
+	// checked on their own, so tiles two of them would both need are not counted against each
+	// other. BonusScore is what the word earns on the path.
+	Bonus      bool `json:"bonus"`
+	BonusScore int  `json:"bonusScore"`
+}
+
+type RowPlacement struct {
+	Row int `json:"row"`
+	// Col is where the word starts
+	Col   int `json:"col"`
+	Score int `json:"score"`
+}
+
+type Params struct {
+	fx.In
+
+	Rules    entity.Rules
+	Scorer   scorer.Controller
+	WordList *entity.WordList
+}
+
+type Result struct {
+	fx.Out
+
+	Controller
+}
+
+type controller struct {
+	rules    entity.Rules
+	scorer   scorer.Controller
+	wordList *entity.WordList
+}
+
+func New(p Params) (Result, error) {
+	return Result{
+		Controller: &controller{
+			rules:    p.Rules,
+			scorer:   p.Scorer,
+			wordList: p.WordList,
+		},
+	}, nil
+}
+
+func (c *controller) Words(ctx context.Context, q Query) ([]Match, error) {
+	pattern := []rune(strings.ToUpper(q.Pattern))
+	start := 0
+	for start < len(pattern) && pattern[start] == Blank {
+		start++
+	}
+	end := len(pattern)
+	for end > start && pattern[end-1] == Blank {
+		end--
+	}
+	word := pattern[start:end]
+	if len(word) == 0 {
+		return nil, invalidQuery("pattern has no letters: %s", q.Pattern)
+	}
+	for _, letter := range word {
+		if letter != AnyLetter && !unicode.IsLetter(letter) {
+			return nil, invalidQuery("unexpected %q in pattern %s", letter, q.Pattern)
+		}
+	}
+	if q.Board != nil {
+		if len(pattern) > q.Board.Size.Cols {
+			return nil, invalidQuery("pattern %s is longer than the board is wide", q.Pattern)
+		}
+		if q.Row < 0 || q.Row >= q.Board.Size.Rows {
+			return nil, invalidQuery("row %d is not on the board", q.Row)
+		}
+	}
+
+	matches := []Match{}
+	for _, found := range c.match(word, q.Tiles, c.rules.MaxWildcards) {
+		match := Match{Word: found, Col: start}
+		if q.Board != nil {
+			score, err := c.scoreRow(ctx, q.Board, q.Row, match)
+			if errors.Is(err, scorer.InvalidLetterError{}) {
+				// Needs more of a letter than the board has
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			match.Score = score
+		}
+		matches = append(matches, match)
+	}
+
+	// Stable, so ties stay alphabetical
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		return b.Score - a.Score
+	})
+	return matches, nil
+}
+
+// match walks the word list along the pattern, in alphabetical order. With tiles, letters beyond
+// them need one of maxWildcards.
+func (c *controller) match(pattern []rune, tiles map[rune]int, maxWildcards int) []string {
+	words := []string{}
+	var used map[rune]int
+	if tiles != nil {
+		used = map[rune]int{}
+	}
+	var walk func(node *entity.DAGNode, letters []rune, wildcards int)
+	walk = func(node *entity.DAGNode, letters []rune, wildcards int) {
+		if len(letters) == len(pattern) {
+			if node.IsWord {
+				words = append(words, string(letters))
+			}
+			return
+		}
+		for _, edge := range node.Edges {
+			if want := pattern[len(letters)]; want != AnyLetter && want != edge.Letter {
+				continue
+			}
+			nextWildcards := wildcards
+			usedTile := false
+			if used != nil {
+				if used[edge.Letter] < tiles[edge.Letter] {
+					used[edge.Letter]++
+					usedTile = true
+				} else if wildcards < maxWildcards {
+					nextWildcards++
+				} else {
+					continue
+				}
+			}
+			walk(edge.Node, append(letters, edge.Letter), nextWildcards)
+			if usedTile {
+				used[edge.Letter]--
+			}
+		}
+	}
+	walk(c.wordList.Root, make([]rune, 0, len(pattern)), 0)
+	return words
+}
+
+func (c *controller) Anagrams(_ context.Context, board *entity.Board, useWildcards bool) []AnagramGroup {
+	tiles := make(map[rune]int, len(board.Tiles))
+	for letter, tile := range board.Tiles {
+		tiles[letter] = tile.Count
+	}
+	maxWildcards := 0
+	if useWildcards {
+		maxWildcards = c.rules.MaxWildcards
+	}
+
+	groups := []AnagramGroup{}
+	for length := c.rules.MinWordLength; length <= c.rules.MaxWordLength; length++ {
+		pattern := []rune(strings.Repeat(string(AnyLetter), length))
+		group := AnagramGroup{Length: length, Words: []Anagram{}}
+		for _, word := range c.match(pattern, tiles, maxWildcards) {
+			group.Words = append(group.Words, anagramValue(board, word))
+		}
+		// Stable, so ties stay alphabetical
+		slices.SortStableFunc(group.Words, func(a, b Anagram) int {
+			return b.Value - a.Value
+		})
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func (c *controller) Placements(ctx context.Context, board *entity.Board) ([]Placement, error) {
+	tiles := make(map[rune]int, len(board.Tiles))
+	for letter, tile := range board.Tiles {
+		tiles[letter] = tile.Count
+	}
+
+	placements := []Placement{}
+	for length := c.rules.MinWordLength; length <= min(c.rules.MaxWordLength, board.Size.Cols); length++ {
+		pattern := []rune(strings.Repeat(string(AnyLetter), length))
+		for _, word := range c.match(pattern, tiles, c.rules.MaxWildcards) {
+			placement := Placement{Word: word, Best: RowPlacement{Row: -1}}
+			for row := range board.Size.Rows {
+				best := RowPlacement{Row: row, Col: -1}
+				// Leading blanks shift the word onto different multipliers
+				for col := 0; col+length <= board.Size.Cols; col++ {
+					score, err := c.scoreRow(ctx, board, row, Match{Word: word, Col: col})
+					if err != nil {
+						return nil, err
+					}
+					if best.Col == -1 || score > best.Score {
+						best.Col, best.Score = col, score
+					}
+				}
+				placement.Rows = append(placement.Rows, best)
+				if placement.Best.Row == -1 || best.Score > placement.Best.Score {
+					placement.Best = best
+				}
+			}
+			if length == len(board.BonusWord) && c.fitsBonusPath(board, word, tiles) {
+				score, err := c.scoreBonus(ctx, board, word)
+				if err != nil {
+					return nil, err
+				}
+				placement.Bonus = true
+				placement.BonusScore = score
+			}
+			placements = append(placements, placement)
+		}
+	}
+
+	// Stable, so ties stay shortest then alphabetical
+	slices.SortStableFunc(placements, func(a, b Placement) int {
+		return b.Best.Score - a.Best.Score
+	})
+	return placements, nil
+}
+
+// fitsBonusPath is whether every row the bonus path crosses has a playable word that puts word's
+// letters on the path's cells in that row
+func (c *controller) fitsBonusPath(board *entity.Board, word string, tiles map[rune]int) bool {
+	letters := []rune(word)
+	// Row to column to the letter the bonus word needs there
+	fixed := map[int]map[int]rune{}
+	for i, coord := range board.BonusWord {
+		if fixed[coord[0]] == nil {
+			fixed[coord[0]] = map[int]rune{}
+		}
+		fixed[coord[0]][coord[1]] = letters[i]
+	}
+	for _, cols := range fixed {
+		first, last := board.Size.Cols, -1
+		for col := range cols {
+			first, last = min(first, col), max(last, col)
+		}
+		fits := false
+		// Any word covering every bonus cell in the row will do
+		for start := 0; start <= first && !fits; start++ {
+			for end := last + 1; end <= board.Size.Cols && !fits; end++ {
+				if !c.rules.IsPlayableLength(end - start) {
+					continue
+				}
+				pattern := []rune(strings.Repeat(string(AnyLetter), end-start))
+				for col, letter := range cols {
+					pattern[col-start] = letter
+				}
+				fits = len(c.match(pattern, tiles, c.rules.MaxWildcards)) > 0
+			}
+		}
+		if !fits {
+			return false
+		}
+	}
+	return true
+}
+
+// anagramValue counts tiles up to what the board has. Any letters beyond that are wildcards.
+func anagramValue(board *entity.Board, word string) Anagram {
+	anagram := Anagram{Word: word}
+	used := map[rune]int{}
+	for _, letter := range word {
+		used[letter]++
+		if used[letter] > board.Tiles[letter].Count {
+			anagram.Wildcard = true
+			continue
+		}
+		anagram.Value += board.Tiles[letter].Value
+	}
+	return anagram
+}
+
+// scoreRow is what a match would score as the only word on the board
+func (c *controller) scoreRow(ctx context.Context, board *entity.Board, row int, match Match) (int, error) {
+	solution := entity.EmptySolution(board.Size)
+	for i, letter := range match.Word {
+		solution.Set(row, match.Col+i, letter)
+	}
+	breakdown, err := c.scorer.Explain(ctx, board, solution)
+	if err != nil {
+		return 0, err
+	}
+	for _, word := range breakdown.Words {
+		if !word.Bonus {
+			return word.Score, nil
+		}
+	}
+	return 0, nil
+}
+
+// ParseTiles reads an inventory written as letters, like "AABCE"
+func ParseTiles(letters string) map[rune]int {
+	tiles := map[rune]int{}
+	for _, letter := range strings.ToUpper(letters) {
+		if unicode.IsLetter(letter) {
+			tiles[letter]++
+		}
+	}
+	return tiles
+}
+
+// scoreBonus is what a word would score as the only word on the board, laid along the bonus path
+func (c *controller) scoreBonus(ctx context.Context, board *entity.Board, word string) (int, error) {
+	solution := entity.EmptySolution(board.Size)
+	for i, letter := range []rune(word) {
+		solution.Set(board.BonusWord[i][0], board.BonusWord[i][1], letter)
+	}
+	breakdown, err := c.scorer.Explain(ctx, board, solution)
+	if err != nil {
+		return 0, err
+	}
+	for _, word := range breakdown.Words {
+		if word.Bonus {
+			return word.Score, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,261 @@
+package query
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azhu2/bongo/src/controller/parser"
+	"github.com/azhu2/bongo/src/controller/scorer"
+	"github.com/azhu2/bongo/src/controller/wordlist"
+	"github.com/azhu2/bongo/src/entity"
+	"github.com/azhu2/bongo/testdata"
+)
+
+var board = &entity.Board{
+	Size: entity.Size{Rows: 2, Cols: 4},
+	Tiles: map[rune]entity.Tile{
+		'C': {Value: 30, Count: 1},
+		'B': {Value: 20, Count: 1},
+		'T': {Value: 5, Count: 2},
+		'A': {Value: 1, Count: 3},
+		'S': {Value: 2, Count: 1},
+	},
+	Multipliers: [][]int{
+		{1, 1, 1, 2},
+		{1, 1, 1, 1},
+	},
+	BonusWord: [][]int{{0, 0}, {1, 1}, {1, 2}},
+}
+
+const testdataDir = "../../../testdata"
+
+var boardWords = testdata.StaticWordList{"BAT", "BATS", "CAT", "CATS", "TAB", "TABS", "TACT"}
+
+// newController queries words with the default rules. Words are common unless overridden.
+func newController(t *testing.T, words testdata.StaticWordList, overrides ...entity.Override) Controller {
+	rules := entity.DefaultRules()
+	builder, err := wordlist.New(wordlist.Params{
+		Rules:     rules,
+		Importer:  words,
+		Overrides: testdata.StaticOverrides(overrides),
+	})
+	require.NoError(t, err)
+	list, err := builder.BuildWordList(context.Background())
+	require.NoError(t, err)
+	scorerResult, err := scorer.New(scorer.Params{Rules: rules, WordList: list})
+	require.NoError(t, err)
+	result, err := New(Params{Rules: rules, Scorer: scorerResult.Controller, WordList: list})
+	require.NoError(t, err)
+	return result.Controller
+}
+
+func words(matches []Match) []string {
+	words := []string{}
+	for _, match := range matches {
+		words = append(words, match.Word)
+	}
+	return words
+}
+
+func TestWords(t *testing.T) {
+	ctx := context.Background()
+	c := newController(t, boardWords)
+
+	tests := []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{
+			name:     "any letters",
+			query:    Query{Pattern: "?AT"},
+			expected: []string{"BAT", "CAT"},
+		},
+		{
+			name:     "lowercase",
+			query:    Query{Pattern: "ta??"},
+			expected: []string{"TABS", "TACT"},
+		},
+		{
+			name:     "blanks",
+			query:    Query{Pattern: "_??T"},
+			expected: []string{"BAT", "CAT"},
+		},
+		{
+			// Only one T, but the wildcard can stand in for the second
+			name:     "tiles",
+			query:    Query{Pattern: "????", Tiles: ParseTiles("batsc")},
+			expected: []string{"BATS", "CATS", "TABS", "TACT"},
+		},
+		{
+			// CATS and TACT would need two wildcards
+			name:     "tiles out of wildcards",
+			query:    Query{Pattern: "????", Tiles: ParseTiles("bat")},
+			expected: []string{"BATS", "TABS"},
+		},
+		{
+			name:     "scored",
+			query:    Query{Pattern: "?AT?", Board: board},
+			expected: []string{"CATS", "BATS"},
+		},
+		{
+			name:     "no matches",
+			query:    Query{Pattern: "Z??"},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := c.Words(ctx, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, words(matches))
+		})
+	}
+}
+
+func TestWords_Score(t *testing.T) {
+	ctx := context.Background()
+	c := newController(t, boardWords)
+
+	// The last cell doubles, so the same word is worth more ending there
+	matches, err := c.Words(ctx, Query{Pattern: "_???", Board: board, Row: 0})
+	require.NoError(t, err)
+	// (5 + 1 + 20*2) * 1.3 rounded up, then (30 + 1 + 5*2) * 1.3 and (20 + 1 + 5*2) * 1.3
+	assert.Equal(t, []Match{{Word: "TAB", Col: 1, Score: 60}, {Word: "CAT", Col: 1, Score: 54}, {Word: "BAT", Col: 1, Score: 41}}, matches)
+
+	matches, err = c.Words(ctx, Query{Pattern: "???_", Board: board, Row: 1})
+	require.NoError(t, err)
+	assert.Equal(t, Match{Word: "CAT", Col: 0, Score: 47}, matches[0])
+}
+
+func TestWords_Invalid(t *testing.T) {
+	ctx := context.Background()
+	c := newController(t, boardWords)
+
+	for _, pattern := range []string{"", "___", "C_T", "C1T"} {
+		_, err := c.Words(ctx, Query{Pattern: pattern})
+		assert.ErrorIs(t, err, InvalidQueryError{}, pattern)
+	}
+	_, err := c.Words(ctx, Query{Pattern: "?????", Board: board})
+	assert.ErrorIs(t, err, InvalidQueryError{}, "wider than the board")
+	_, err = c.Words(ctx, Query{Pattern: "???", Board: board, Row: 2})
+	assert.ErrorIs(t, err, InvalidQueryError{}, "row off the board")
+}
+
+func TestAnagrams(t *testing.T) {
+	ctx := context.Background()
+	c := newController(t, boardWords)
+
+	groups := c.Anagrams(ctx, board, true)
+	assert.Equal(t, []AnagramGroup{
+		{Length: 3, Words: []Anagram{{Word: "CAT", Value: 36}, {Word: "BAT", Value: 26}, {Word: "TAB", Value: 26}}},
+		{Length: 4, Words: []Anagram{{Word: "TACT", Value: 41}, {Word: "CATS", Value: 38}, {Word: "BATS", Value: 28}, {Word: "TABS", Value: 28}}},
+		{Length: 5, Words: []Anagram{}},
+	}, groups)
+
+	// With one T, TACT needs the wildcard, which is worth nothing
+	oneT := *board
+	oneT.Tiles = maps.Clone(board.Tiles)
+	oneT.Tiles['T'] = entity.Tile{Value: 5, Count: 1}
+	groups = c.Anagrams(ctx, &oneT, true)
+	assert.Equal(t, Anagram{Word: "TACT", Value: 36, Wildcard: true}, groups[1].Words[1])
+	groups = c.Anagrams(ctx, &oneT, false)
+	assert.Equal(t, []string{"CATS", "BATS", "TABS"}, anagramWords(groups[1].Words))
+}
+
+func anagramWords(anagrams []Anagram) []string {
+	words := []string{}
+	for _, anagram := range anagrams {
+		words = append(words, anagram.Word)
+	}
+	return words
+}
+
+func TestPlacements(t *testing.T) {
+	ctx := context.Background()
+	c := newController(t, boardWords)
+
+	placements, err := c.Placements(ctx, board)
+	require.NoError(t, err)
+	require.Len(t, placements, 7)
+	// B on the doubled last cell of row 0 beats everything on row 1
+	assert.Equal(t, Placement{
+		Word: "TAB",
+		Rows: []RowPlacement{{Row: 0, Col: 1, Score: 60}, {Row: 1, Col: 0, Score: 34}},
+		Best: RowPlacement{Row: 0, Col: 1, Score: 60},
+		// No multipliers on the bonus path
+		Bonus:      true,
+		BonusScore: 34,
+	}, placements[0])
+	assert.False(t, placements[len(placements)-1].Bonus, "four letters don't fit the bonus path")
+}
+
+// The top "Highest Scoring Words" band from each puzzle's METADATA
+func TestPlacements_MetadataBands(t *testing.T) {
+	ctx := context.Background()
+	parserResult, err := parser.New(parser.Params{Rules: entity.DefaultRules()})
+	require.NoError(t, err)
+	// Only scores without the common multiplier fit these words in their band
+	uncommon := map[string][]entity.Override{
+		"2024-12-24": {{Action: entity.OverrideUncommon, Word: "NAGGY"}},
+	}
+	for _, tt := range testdata.TestData {
+		t.Run(tt.Date, func(t *testing.T) {
+			raw, err := os.ReadFile(filepath.Join(testdataDir, tt.Date+".txt"))
+			require.NoError(t, err)
+			metadata, err := parserResult.Controller.ParseMetadata(ctx, string(raw))
+			require.NoError(t, err)
+			require.NotEmpty(t, metadata.ScoreBands)
+			band := metadata.ScoreBands[0]
+			// The list is just the band's words, so this checks how they're valued, not which
+			// words a dictionary has
+			c := newController(t, band.Words, uncommon[tt.Date]...)
+
+			placements, err := c.Placements(ctx, tt.Board)
+			require.NoError(t, err)
+			best := map[string]int{}
+			for _, placement := range placements {
+				best[placement.Word] = placement.Best.Score
+			}
+			for _, word := range band.Words {
+				require.Contains(t, best, word)
+				assert.GreaterOrEqual(t, best[word], band.Low, word)
+				assert.LessOrEqual(t, best[word], band.High, word)
+			}
+		})
+	}
+}
+
+func TestPlacements_BonusPath(t *testing.T) {
+	ctx := context.Background()
+	c := newController(t, testdata.StaticWordList{"ATE", "CAT", "TEA"})
+	// The path ends row 0 and starts row 1, so row 0 needs a word ending in the bonus word's
+	// first letter and row 1 one starting with the other two
+	pathBoard := &entity.Board{
+		Size: entity.Size{Rows: 2, Cols: 3},
+		Tiles: map[rune]entity.Tile{
+			'A': {Value: 1, Count: 2},
+			'C': {Value: 1, Count: 2},
+			'E': {Value: 1, Count: 2},
+			'T': {Value: 1, Count: 2},
+		},
+		Multipliers: [][]int{{1, 1, 1}, {1, 1, 1}},
+		BonusWord:   [][]int{{0, 2}, {1, 0}, {1, 1}},
+	}
+
+	placements, err := c.Placements(ctx, pathBoard)
+	require.NoError(t, err)
+	bonus := map[string]bool{}
+	for _, placement := range placements {
+		bonus[placement.Word] = placement.Bonus
+	}
+	// TEA ends row 0 with A and starts row 1 with TE
+	assert.Equal(t, map[string]bool{"ATE": true, "CAT": false, "TEA": false}, bonus)
+}
@@ -0,0 +1,46 @@
+package scorer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhu2/bongo/src/entity"
+)
+
+// Breakdown explains how a solution was scored
+type Breakdown struct {
+	Score int
+	// Words are each scored row word, then the bonus word
+	Words []WordScore
+	// Wildcards are the cells the wildcard was assigned to
+	Wildcards []entity.Cell
+	// Tiles is the tile each filled cell consumed
+	Tiles map[entity.Cell]entity.TileRef
+}
+
+type WordScore struct {
+	Word  string
+	Cells []entity.Cell
+	Bonus bool
+	// Multiplier is 0 for invalid words, otherwise 1 or the common word multiplier
+	Multiplier float64
+	// LetterScore is the sum of tile values times cell multipliers, before the word multiplier
+	LetterScore int
+	Score       int
+}
+
+func (b Breakdown) String() string {
+	var sb strings.Builder
+	for _, word := range b.Words {
+		label := fmt.Sprintf("row %d", word.Cells[0].Row)
+		if word.Bonus {
+			label = "bonus"
+		}
+		fmt.Fprintf(&sb, "%-6s %-5s %4d x %.2g = %d\n", label, word.Word, word.LetterScore, word.Multiplier, word.Score)
+	}
+	for _, cell := range b.Wildcards {
+		fmt.Fprintf(&sb, "wildcard (%d,%d)\n", cell.Row, cell.Col)
+	}
+	fmt.Fprintf(&sb, "total %d", b.Score)
+	return sb.String()
+}
@@ -2,8 +2,9 @@ package scorer
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"math"
+	"slices"
 	"strings"
 
 	"go.uber.org/fx"
@@ -17,11 +18,14 @@ var Module = fx.Module("scorer",
 
 type Controller interface {
 	Score(context.Context, *entity.Board, entity.Solution) (int, error)
+	// Explain scores a solution and shows where the points came from
+	Explain(context.Context, *entity.Board, entity.Solution) (*Breakdown, error)
 }
 
 type Params struct {
 	fx.In
 
+	Rules    entity.Rules
 	WordList *entity.WordList
 }
 
@@ -32,115 +36,182 @@ type Result struct {
 }
 
 type scorer struct {
+	rules    entity.Rules
 	wordList *entity.WordList
 }
 
 func New(p Params) (Result, error) {
 	return Result{
 		Controller: &scorer{
+			rules:    p.Rules,
 			wordList: p.WordList,
 		},
 	}, nil
 }
 
+// Score is Explain without the breakdown, which the solver doesn't need for every board it tries
 func (s *scorer) Score(ctx context.Context, board *entity.Board, solution entity.Solution) (int, error) {
-	score := 0
-	wildcardCount := 0
+	ledger, words, err := s.place(ctx, board, solution)
+	if err != nil {
+		return 0, err
+	}
+	return ledger.ChooseWildcards(func(wildcards []entity.Cell) int {
+		return scoreWords(board, words, wildcards)
+	}), nil
+}
+
+func (s *scorer) Explain(ctx context.Context, board *entity.Board, solution entity.Solution) (*Breakdown, error) {
+	ledger, words, err := s.place(ctx, board, solution)
+	if err != nil {
+		return nil, err
+	}
 
-	availableLetters := map[rune]int{}
-	for letter, tile := range board.Tiles {
-		availableLetters[letter] = tile.Count
+	// Put the wildcard wherever it costs the fewest points
+	ledger.ChooseWildcards(func(wildcards []entity.Cell) int {
+		return scoreWords(board, words, wildcards)
+	})
+	wildcards := ledger.Wildcards()
+	breakdown := Breakdown{
+		Wildcards: wildcards,
+		Tiles:     map[entity.Cell]entity.TileRef{},
 	}
-	letterValues := make([][]int, entity.BoardSize)
-	for i := 0; i < entity.BoardSize; i++ {
-		letterValues[i] = make([]int, entity.BoardSize)
-		for j := 0; j < entity.BoardSize; j++ {
-			letterValues[i][j] = -1
+	for _, word := range words {
+		word.LetterScore = scoreLetters(board, word, wildcards)
+		word.Score = int(math.Ceil(word.Multiplier * float64(word.LetterScore)))
+		breakdown.Score += word.Score
+		breakdown.Words = append(breakdown.Words, word)
+	}
+	for row := 0; row < board.Size.Rows; row++ {
+		for col := 0; col < board.Size.Cols; col++ {
+			cell := entity.Cell{Row: row, Col: col}
+			if tile, ok := ledger.Tile(cell); ok {
+				breakdown.Tiles[cell] = tile
+			}
 		}
 	}
 
-	// Count rows
-	for rowIdx, row := range solution.Rows() {
-		wordScore := 0
-		for colIdx, letter := range row {
-			letterScore, err := scoreLetter(ctx, board, availableLetters, rowIdx, colIdx, letter)
-			if err != nil {
-				if errors.Is(err, InvalidLetterError{}) {
-					wildcardCount++
-				}
-				if wildcardCount > entity.MaxWildcards {
-					return 0, err
-				}
-			} else {
-				letterValues[rowIdx][colIdx] = letterScore
-			}
-			wordScore += letterScore
+	return &breakdown, nil
+}
+
+// place records the solution's tiles in a ledger and finds the words it scores
+func (s *scorer) place(ctx context.Context, board *entity.Board, solution entity.Solution) (*entity.Ledger, []WordScore, error) {
+	if solution.Size != board.Size {
+		return nil, nil, fmt.Errorf("solution size %dx%d does not match board size %dx%d",
+			solution.Size.Cols, solution.Size.Rows, board.Size.Cols, board.Size.Rows)
+	}
+
+	// Every placed letter uses up a tile, whether or not its row is a word.
+	// Wildcards marked on the solution go first so that they are not inferred elsewhere.
+	ledger := entity.NewLedger(board, s.rules.MaxWildcards)
+	for _, cell := range solution.WildcardCells() {
+		letter := solution.Get(cell.Row, cell.Col)
+		if _, ok := ledger.PlaceWildcard(cell, letter); !ok {
+			return nil, nil, InvalidLetterError{letter: letter}
 		}
-		multiplier := s.wordMultiplier(ctx, string(row))
-		score += (int)(math.Ceil(multiplier * float64(wordScore)))
-		if multiplier == 0 {
-			// Return letters to availability pool if word is invalid
-			for _, letter := range row {
-				if availableLetters[letter] > 0 {
-					availableLetters[letter]++
-				}
+	}
+	for row := 0; row < board.Size.Rows; row++ {
+		for col := 0; col < board.Size.Cols; col++ {
+			letter := solution.Get(row, col)
+			if letter == ' ' || solution.IsWildcard(row, col) {
+				continue
+			}
+			if _, ok := ledger.Place(entity.Cell{Row: row, Col: col}, letter); !ok {
+				return nil, nil, InvalidLetterError{letter: letter}
 			}
 		}
 	}
 
-	// Count bonus word
+	// Which words count only depends on the letters, not on where the wildcard goes
+	words := []WordScore{}
+	for rowIdx, row := range solution.Rows() {
+		for _, bounds := range s.rowWords(row) {
+			word := WordScore{
+				Word:       string(row[bounds[0]:bounds[1]]),
+				Multiplier: s.wordMultiplier(ctx, string(row[bounds[0]:bounds[1]])),
+			}
+			for col := bounds[0]; col < bounds[1]; col++ {
+				word.Cells = append(word.Cells, entity.Cell{Row: rowIdx, Col: col})
+			}
+			words = append(words, word)
+		}
+	}
+	bonus := WordScore{Bonus: true}
 	bonusLetters := make([]rune, len(board.BonusWord))
-	bonusScore := 0
 	for i, coords := range board.BonusWord {
-		rowIdx := coords[0]
-		colIdx := coords[1]
-		letter := solution.Get(rowIdx, colIdx)
-		bonusLetters[i] = letter
-		letterScore := letterValues[rowIdx][colIdx]
-		bonusScore += letterScore
+		bonusLetters[i] = solution.Get(coords[0], coords[1])
+		bonus.Cells = append(bonus.Cells, entity.Cell{Row: coords[0], Col: coords[1]})
+	}
+	bonus.Word = string(bonusLetters)
+	if !strings.ContainsRune(bonus.Word, ' ') {
+		bonus.Multiplier = s.wordMultiplier(ctx, bonus.Word)
 	}
-	score += (int)(math.Ceil(s.wordMultiplier(ctx, string(bonusLetters)) * float64(bonusScore)))
+	words = append(words, bonus)
+	return ledger, words, nil
+}
 
-	return score, nil
+func scoreWords(board *entity.Board, words []WordScore, wildcards []entity.Cell) int {
+	score := 0
+	for _, word := range words {
+		if word.Multiplier == 0 {
+			continue
+		}
+		score += int(math.Ceil(word.Multiplier * float64(scoreLetters(board, word, wildcards))))
+	}
+	return score
 }
 
-func scoreLetter(_ context.Context, board *entity.Board, availableLetters map[rune]int, row, col int, letter rune) (int, error) {
-	if letter == ' ' {
-		return 0, nil
+// scoreLetters sums tile values times cell multipliers. Wildcards and blanks are worth nothing.
+func scoreLetters(board *entity.Board, word WordScore, wildcards []entity.Cell) int {
+	score := 0
+	for i, letter := range []rune(word.Word) {
+		cell := word.Cells[i]
+		if letter == ' ' || slices.Contains(wildcards, cell) {
+			continue
+		}
+		score += board.Multipliers[cell.Row][cell.Col] * board.Tiles[letter].Value
 	}
-	tile, ok := board.Tiles[letter]
-	if !ok || availableLetters[letter] < 1 {
-		return 0, InvalidLetterError{letter: letter}
+	return score
+}
+
+// rowWords returns the [start, end) bounds of each word to score in a row
+func (s *scorer) rowWords(row []rune) [][2]int {
+	words := [][2]int{}
+	start := -1
+	for col, letter := range row {
+		if letter != ' ' && start == -1 {
+			start = col
+		}
+		if letter == ' ' && start != -1 && !s.rules.OneWordPerRow {
+			words = append(words, [2]int{start, col})
+			start = -1
+		}
 	}
-	availableLetters[letter]--
-	return board.Multipliers[row][col] * tile.Value, nil
+	if start != -1 {
+		end := len(row)
+		for row[end-1] == ' ' {
+			end--
+		}
+		words = append(words, [2]int{start, end})
+	}
+	return words
 }
 
 func (s *scorer) wordMultiplier(ctx context.Context, word string) float64 {
-	// Assume no gaps. I'm pretty sure only one word per line is counted.
+	// With one word per row, gaps make the whole row invalid
 	trimmed := strings.TrimSpace(word)
 	if !s.isWord(ctx, trimmed) {
 		return 0
 	}
 	if s.isCommon(ctx, trimmed) {
-		return entity.CommonMultiplier
+		return s.rules.CommonMultiplier
 	}
 	return 1
 }
 
 func (s *scorer) isWord(_ context.Context, word string) bool {
-	node := s.wordList.Root
-	for _, letter := range word {
-		if child := node.Children[letter]; child != nil {
-			node = child
-			continue
-		}
-		return false
-	}
-	return node.IsWord
+	return s.wordList.Contains(word)
 }
 
-func (s *scorer) isCommon(_ context.Context, _ string) bool {
-	// TODO Implement
-	return true
+func (s *scorer) isCommon(_ context.Context, word string) bool {
+	return s.wordList.IsCommon(word)
 }
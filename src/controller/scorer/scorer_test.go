@@ -8,7 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/azhu2/bongo/src/controller/wordlist"
-	"github.com/azhu2/bongo/src/gateway/wordlistimporter"
+	"github.com/azhu2/bongo/src/entity"
 	"github.com/azhu2/bongo/testdata"
 )
 
@@ -16,13 +16,11 @@ func TestScore(t *testing.T) {
 	for _, tt := range testdata.TestData {
 		t.Run(tt.Date, func(t *testing.T) {
 			ctx := context.Background()
-			importerGateway, err := wordlistimporter.New()
-			require.NoError(t, err)
-			wordlistBuilder, err := wordlist.New(wordlist.Params{Importer: importerGateway.Gateway})
+			wordlistBuilder, err := wordlist.New(wordlist.Params{Rules: entity.DefaultRules(), Importer: testdata.StaticWordList(testdata.Words)})
 			require.NoError(t, err)
 			wordlist, err := wordlistBuilder.BuildWordList(ctx)
 			require.NoError(t, err)
-			result, _ := New(Params{WordList: wordlist})
+			result, _ := New(Params{Rules: entity.DefaultRules(), WordList: wordlist})
 			s := result.Controller
 
 			score, err := s.Score(context.Background(), tt.Board, tt.Solution)
@@ -31,3 +29,180 @@ func TestScore(t *testing.T) {
 		})
 	}
 }
+
+func TestScore_Rules(t *testing.T) {
+	board := &entity.Board{
+		Size: entity.Size{Rows: 1, Cols: 7},
+		Tiles: map[rune]entity.Tile{
+			'C': {Value: 3, Count: 1},
+			'B': {Value: 3, Count: 1},
+			'E': {Value: 1, Count: 1},
+			'A': {Value: 1, Count: 2},
+			'T': {Value: 1, Count: 2},
+		},
+		Multipliers: [][]int{{1, 1, 1, 1, 1, 1, 1}},
+		BonusWord:   [][]int{{0, 0}, {0, 1}, {0, 2}},
+	}
+	oneWord := entity.DefaultRules()
+	manyWords := entity.DefaultRules()
+	manyWords.OneWordPerRow = false
+	generous := manyWords
+	generous.CommonMultiplier = 1.5
+	strict := entity.DefaultRules()
+	strict.MaxWildcards = 0
+
+	tests := []struct {
+		name     string
+		rules    entity.Rules
+		solution string
+		score    int
+		err      bool
+	}{
+		{name: "one word per row", rules: oneWord, solution: "CAT TAB", score: 7},
+		{name: "many words per row", rules: manyWords, solution: "CAT TAB", score: 21},
+		{name: "common multiplier", rules: generous, solution: "CAT TAB", score: 24},
+		{name: "wildcard", rules: manyWords, solution: "CAB TAB", score: 23},
+		{name: "no wildcards", rules: strict, solution: "CAB TAB", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			wordlistBuilder, err := wordlist.New(wordlist.Params{Rules: tt.rules, Importer: testdata.StaticWordList{"CAB", "CAT", "TAB"}})
+			require.NoError(t, err)
+			list, err := wordlistBuilder.BuildWordList(ctx)
+			require.NoError(t, err)
+			result, _ := New(Params{Rules: tt.rules, WordList: list})
+
+			score, err := result.Controller.Score(ctx, board, entity.NewSolution(board.Size, tt.solution))
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.score, score)
+		})
+	}
+}
+
+func TestScore_Uncommon(t *testing.T) {
+	ctx := context.Background()
+	board := &entity.Board{
+		Size:        entity.Size{Rows: 1, Cols: 7},
+		Tiles:       map[rune]entity.Tile{'C': {Value: 3, Count: 1}, 'B': {Value: 3, Count: 1}, 'A': {Value: 1, Count: 2}, 'T': {Value: 1, Count: 2}},
+		Multipliers: [][]int{{1, 1, 1, 1, 1, 1, 1}},
+		BonusWord:   [][]int{{0, 0}, {0, 1}, {0, 2}},
+	}
+	rules := entity.DefaultRules()
+	rules.OneWordPerRow = false
+	wordlistBuilder, err := wordlist.New(wordlist.Params{
+		Rules:     rules,
+		Importer:  testdata.StaticWordList{"CAT", "TAB"},
+		Overrides: testdata.StaticOverrides{{Action: entity.OverrideUncommon, Word: "TAB"}},
+	})
+	require.NoError(t, err)
+	list, err := wordlistBuilder.BuildWordList(ctx)
+	require.NoError(t, err)
+	result, _ := New(Params{Rules: rules, WordList: list})
+
+	breakdown, err := result.Controller.Explain(ctx, board, entity.NewSolution(board.Size, "CAT TAB"))
+	require.NoError(t, err)
+	// CAT gets the common multiplier as a row and as the bonus word, TAB doesn't
+	assert.Equal(t, 1.3, breakdown.Words[0].Multiplier)
+	assert.Equal(t, 1.0, breakdown.Words[1].Multiplier)
+	assert.Equal(t, 7+5+7, breakdown.Score)
+}
+
+func TestExplain_Wildcards(t *testing.T) {
+	// Bonus word is the last three cells, so the wildcard should avoid them
+	board := &entity.Board{
+		Size: entity.Size{Rows: 1, Cols: 7},
+		Tiles: map[rune]entity.Tile{
+			'C': {Value: 3, Count: 1},
+			'B': {Value: 3, Count: 1},
+			'A': {Value: 1, Count: 2},
+			'T': {Value: 1, Count: 3},
+		},
+		Multipliers: [][]int{{1, 1, 1, 1, 1, 1, 1}},
+		BonusWord:   [][]int{{0, 4}, {0, 5}, {0, 6}},
+	}
+	manyWords := entity.DefaultRules()
+	manyWords.OneWordPerRow = false
+
+	tests := []struct {
+		name      string
+		rules     entity.Rules
+		solution  string
+		score     int
+		wildcards []entity.Cell
+	}{
+		{
+			name:      "overused letter",
+			rules:     manyWords,
+			solution:  "CAB TAB",
+			score:     20,
+			wildcards: []entity.Cell{{Row: 0, Col: 2}},
+		},
+		{
+			name:      "letter not on board",
+			rules:     manyWords,
+			solution:  "CAX TAB",
+			score:     14,
+			wildcards: []entity.Cell{{Row: 0, Col: 2}},
+		},
+		{
+			// Marked by the player, so not moved even though it costs points
+			name:      "explicit wildcard",
+			rules:     manyWords,
+			solution:  "CAB TA*B",
+			score:     16,
+			wildcards: []entity.Cell{{Row: 0, Col: 6}},
+		},
+		{
+			// Row is not a word, but its tiles are still used up
+			name:      "invalid row",
+			rules:     entity.DefaultRules(),
+			solution:  "CAB TAB",
+			score:     7,
+			wildcards: []entity.Cell{{Row: 0, Col: 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			wordlistBuilder, err := wordlist.New(wordlist.Params{Rules: tt.rules, Importer: testdata.StaticWordList{"CAB", "CAT", "TAB"}})
+			require.NoError(t, err)
+			list, err := wordlistBuilder.BuildWordList(ctx)
+			require.NoError(t, err)
+			result, _ := New(Params{Rules: tt.rules, WordList: list})
+
+			breakdown, err := result.Controller.Explain(ctx, board, entity.NewSolution(board.Size, tt.solution))
+			require.NoError(t, err)
+			assert.Equal(t, tt.score, breakdown.Score)
+			assert.Equal(t, tt.wildcards, breakdown.Wildcards)
+			for _, cell := range tt.wildcards {
+				assert.True(t, breakdown.Tiles[cell].Wildcard)
+			}
+		})
+	}
+}
+
+// The solver scores every partial board it considers, so Score is on its hot path
+func BenchmarkScore(b *testing.B) {
+	ctx := context.Background()
+	wordlistBuilder, err := wordlist.New(wordlist.Params{Rules: entity.DefaultRules(), Importer: testdata.StaticWordList(testdata.Words)})
+	require.NoError(b, err)
+	wordList, err := wordlistBuilder.BuildWordList(ctx)
+	require.NoError(b, err)
+	result, _ := New(Params{Rules: entity.DefaultRules(), WordList: wordList})
+	tt := testdata.TestData[0]
+	// One letter short of its tiles, so a wildcard has to be placed
+	solution := tt.Solution.Clone()
+	solution.Set(4, 4, 'W')
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := result.Controller.Score(ctx, tt.Board, solution); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -3,12 +3,14 @@ package solver
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"maps"
 	"math"
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"go.uber.org/fx"
 
@@ -21,6 +23,9 @@ const (
 	bonusCandidateMultiplier = 0.6
 )
 
+// ErrNoSolution means no arrangement of the board's tiles scored anything
+var ErrNoSolution = errors.New("no solution found")
+
 var Module = fx.Module("solver",
 	fx.Provide(New),
 )
@@ -32,6 +37,7 @@ type Controller interface {
 type Params struct {
 	fx.In
 
+	Rules    entity.Rules
 	Scorer   scorer.Controller
 	WordList *entity.WordList
 }
@@ -43,14 +49,22 @@ type Result struct {
 }
 
 type solver struct {
-	scorer    scorer.Controller
-	wordList  *entity.WordList
-	bestScore int
+	rules    entity.Rules
+	scorer   scorer.Controller
+	wordList *entity.WordList
 }
 
 func New(p Params) (Result, error) {
+	if err := p.Rules.Validate(); err != nil {
+		return Result{}, err
+	}
+	// evaluateRow fills each row with a single word, so it would miss the rest of the solutions
+	if !p.Rules.OneWordPerRow {
+		return Result{}, fmt.Errorf("solver only supports one word per row")
+	}
 	return Result{
 		Controller: &solver{
+			rules:    p.Rules,
 			scorer:   p.Scorer,
 			wordList: p.WordList,
 		},
@@ -73,11 +87,14 @@ func (s *solver) Solve(ctx context.Context, board *entity.Board) ([]entity.Solut
 
 	var wg sync.WaitGroup
 	best := []entity.Solution{}
+	// Kept per call so one board's best doesn't prune the next, and shared with the row
+	// goroutines so they can prune against it
+	bestScore := &atomic.Int64{}
 
 	// Then seed the recursive row-by-row solver with bonus words already set in grid
 	for _, candidate := range candidates {
 		remainingLetters := maps.Clone(availableLetters)
-		for _, letter := range candidate {
+		for _, letter := range candidate.Letters {
 			if letter != ' ' {
 				remainingLetters[letter]--
 			}
@@ -91,46 +108,70 @@ func (s *solver) Solve(ctx context.Context, board *entity.Board) ([]entity.Solut
 				availableLetters: remainingLetters,
 				wildcardCount:    0,
 				curRow:           0,
-			}, solutionChan)
+			}, bestScore, solutionChan)
 			close(solutionChan)
 		}()
 		for solution := range solutionChan {
-			if solution.score == s.bestScore {
+			if int64(solution.score) == bestScore.Load() {
 				slog.Debug("new best board (tied)", "board", solution.solution, "score", solution.score)
 				best = append(best, solution.solution)
-			} else if solution.score > s.bestScore {
+			} else if int64(solution.score) > bestScore.Load() {
 				slog.Debug("new best board", "board", solution.solution, "score", solution.score)
-				s.bestScore = solution.score
+				bestScore.Store(int64(solution.score))
 				best = []entity.Solution{solution.solution}
 			}
 		}
 	}
 
 	wg.Wait()
+	if len(best) == 0 {
+		return nil, ErrNoSolution
+	}
+
+	// Mark where the wildcard goes so players know which tile to use it for
+	for i, solution := range best {
+		best[i] = s.markWildcards(ctx, board, solution)
+	}
 	return best, nil
 }
 
+func (s *solver) markWildcards(ctx context.Context, board *entity.Board, solution entity.Solution) entity.Solution {
+	breakdown, err := s.scorer.Explain(ctx, board, solution)
+	if err != nil {
+		slog.Error("unable to place wildcards in solution",
+			"solution", solution,
+			"err", err,
+		)
+		return solution
+	}
+	marked := solution.Clone()
+	for _, cell := range breakdown.Wildcards {
+		marked.SetWildcard(cell.Row, cell.Col, true)
+	}
+	return marked
+}
+
 func (s *solver) generateBonusCandidates(ctx context.Context, board *entity.Board) []entity.Solution {
 	candidates := []candidateSolution{}
 
 	maxValue := 0
-	nodes := entity.Stack[*entity.DAGNode]{}
-	nodes.Push(s.wordList.Root)
+	// Nodes are shared between words, so the path so far travels with each one
+	nodes := entity.Stack[entity.Prefix]{}
+	nodes.Push(entity.Prefix{Letters: []rune{}, Node: s.wordList.Root})
 	for !nodes.IsEmpty() {
 		cur := nodes.Pop()
-		for letter, child := range cur.Children {
-			// Assume all bonus tiles used
-			if letter != ' ' {
-				nodes.Push(child)
+		if len(cur.Letters) < len(board.BonusWord) {
+			for _, edge := range cur.Node.Edges {
+				nodes.Push(entity.Prefix{Letters: append(slices.Clone(cur.Letters), edge.Letter), Node: edge.Node})
 			}
 		}
-		if cur.IsWord && len(cur.Fragment) == len(board.BonusWord) {
-			candidate := entity.EmptySolution()
+		if cur.Node.IsWord && len(cur.Letters) == len(board.BonusWord) {
+			candidate := entity.EmptySolution(board.Size)
 
 			// Assume no wildcards in bonus (may not be true)
 			letters := map[rune]int{}
 			isWildCard := false
-			for _, letter := range cur.Fragment {
+			for _, letter := range cur.Letters {
 				letters[letter]++
 				if letters[letter] > board.Tiles[letter].Count {
 					isWildCard = true
@@ -142,7 +183,7 @@ func (s *solver) generateBonusCandidates(ctx context.Context, board *entity.Boar
 			}
 
 			for i, b := range board.BonusWord {
-				candidate.Set(b[0], b[1], cur.Fragment[i])
+				candidate.Set(b[0], b[1], cur.Letters[i])
 			}
 			score, err := s.scorer.Score(ctx, board, candidate)
 			if err != nil {
@@ -174,7 +215,7 @@ func (s *solver) generateBonusCandidates(ctx context.Context, board *entity.Boar
 	for _, candidate := range candidates {
 		if candidate.score >= int(bonusCandidateMultiplier*float64(maxValue)) {
 			bonusBoards = append(bonusBoards, candidate.solution)
-			logMsg += strings.ReplaceAll(strings.ReplaceAll(string(candidate.solution), "|", ""), " ", "") + "|"
+			logMsg += strings.ReplaceAll(string(candidate.solution.Letters), " ", "") + "|"
 		}
 	}
 
@@ -193,20 +234,26 @@ type partialSolution struct {
 }
 
 type partialRow struct {
-	node             *entity.DAGNode
+	node *entity.DAGNode
+	// letters is the path to node, which the node itself does not know
+	letters []rune
+	// offset is the column letters start at. Rows started from the root aren't anchored, so
+	// their words can go at any offset they fit at.
+	offset           int
+	anchored         bool
 	availableLetters map[rune]int
 	wildcardCount    int
 }
 
-func (s *solver) evaluateRow(ctx context.Context, board *entity.Board, partial partialSolution, solutions chan<- candidateSolution) []entity.Solution {
+func (s *solver) evaluateRow(ctx context.Context, board *entity.Board, partial partialSolution, overallBest *atomic.Int64, solutions chan<- candidateSolution) []entity.Solution {
 	// Base case
-	if partial.curRow == entity.BoardSize {
+	if partial.curRow == board.Size.Rows {
 		return []entity.Solution{partial.solution}
 	}
 
 	// Short-circuit if not possible to beat current max
 	max := s.getTheoreticalMax(ctx, board, partial)
-	if max < s.bestScore {
+	if int64(max) < overallBest.Load() {
 		return []entity.Solution{partial.solution}
 	}
 
@@ -221,38 +268,44 @@ func (s *solver) evaluateRow(ctx context.Context, board *entity.Board, partial p
 		}
 	}
 	if filledCol != -1 {
-		// If there are tiles already filled in this row, seed from node map in word list
+		// If there are tiles already filled in this row, seed from node map in word list, with the
+		// word starting anywhere up to the filled tile
 		filledLetter := partial.solution.Get(partial.curRow, filledCol)
-		filledCandidates := s.wordList.NodeMap[filledCol][filledLetter]
-		for _, candidate := range filledCandidates {
-			filledSolution := slices.Clone(partial.solution)
-			remainingLetters := maps.Clone(partial.availableLetters)
-			wildcardCount := partial.wildcardCount
-			// Backfill the earlier letters before this node
-			for col, letter := range candidate.Fragment {
-				if col == filledCol {
-					continue
+		for offset := 0; offset <= filledCol; offset++ {
+			for _, candidate := range s.wordList.Prefixes(filledCol-offset, filledLetter) {
+				remainingLetters := maps.Clone(partial.availableLetters)
+				wildcardCount := partial.wildcardCount
+				fits := true
+				// Backfill the earlier letters before this node, which must agree with any already set
+				for i, letter := range candidate.Letters {
+					if filled := partial.solution.Get(partial.curRow, offset+i); filled != ' ' {
+						fits = fits && filled == letter
+						continue
+					}
+					if remainingLetters[letter] > 0 {
+						remainingLetters[letter]--
+					} else {
+						wildcardCount++
+					}
 				}
-				filledSolution.Set(partial.curRow, col, letter)
-				if remainingLetters[letter] > 0 {
-					remainingLetters[letter]--
-				} else {
-					wildcardCount++
+				if !fits || wildcardCount > s.rules.MaxWildcards {
+					continue
 				}
+				rowCandidates.Push(partialRow{
+					node:             candidate.Node,
+					letters:          candidate.Letters,
+					offset:           offset,
+					anchored:         true,
+					availableLetters: remainingLetters,
+					wildcardCount:    wildcardCount,
+				})
 			}
-			if wildcardCount > entity.MaxWildcards {
-				continue
-			}
-			rowCandidates.Push(partialRow{
-				node:             candidate,
-				availableLetters: remainingLetters,
-				wildcardCount:    wildcardCount,
-			})
 		}
 	} else {
 		// Start with blank row and root of word list
 		rowCandidates.Push(partialRow{
 			node:             s.wordList.Root,
+			letters:          []rune{},
 			availableLetters: partial.availableLetters,
 			wildcardCount:    partial.wildcardCount,
 		})
@@ -260,10 +313,14 @@ func (s *solver) evaluateRow(ctx context.Context, board *entity.Board, partial p
 
 	for !rowCandidates.IsEmpty() {
 		cur := rowCandidates.Pop()
-		for nextLetter, childNode := range cur.node.Children {
-			// Add valid children nodes
+		for _, edge := range cur.node.Edges {
+			// Add valid children nodes that still fit in the row
+			if cur.offset+len(cur.letters) >= board.Size.Cols {
+				break
+			}
+			nextLetter := edge.Letter
 			isLetterAvailable := cur.availableLetters[nextLetter] > 0
-			if !isLetterAvailable && cur.wildcardCount >= entity.MaxWildcards {
+			if !isLetterAvailable && cur.wildcardCount >= s.rules.MaxWildcards {
 				continue
 			}
 			remainingLetters := maps.Clone(cur.availableLetters)
@@ -274,19 +331,33 @@ func (s *solver) evaluateRow(ctx context.Context, board *entity.Board, partial p
 				wildcardCount++
 			}
 			rowCandidates.Push(partialRow{
-				node:             childNode,
+				node:             edge.Node,
+				letters:          append(slices.Clone(cur.letters), nextLetter),
+				offset:           cur.offset,
+				anchored:         cur.anchored,
 				availableLetters: remainingLetters,
 				wildcardCount:    wildcardCount,
 			})
 		}
 
-		// Only score 5-letter fragments to avoid recounting the same candidate with/without spaces.
-		// This words because the DAG is padded with leading and trailing spaces.
-		if cur.node.IsWord && len(cur.node.Fragment) == entity.BoardSize {
-			nextPartial := slices.Clone(partial.solution)
-			nextPartial.SetRow(partial.curRow, cur.node.Fragment)
+		if !cur.node.IsWord {
+			continue
+		}
+		// Words shorter than the row are padded with spaces on either side
+		offsets := []int{cur.offset}
+		if !cur.anchored {
+			offsets = []int{}
+			for offset := 0; offset+len(cur.letters) <= board.Size.Cols; offset++ {
+				offsets = append(offsets, offset)
+			}
+		}
+		for _, offset := range offsets {
+			row := slices.Repeat([]rune{' '}, board.Size.Cols)
+			copy(row[offset:], cur.letters)
+			nextPartial := partial.solution.Clone()
+			nextPartial.SetRow(partial.curRow, row)
 			remainingLetters := maps.Clone(partial.availableLetters)
-			for j, letter := range cur.node.Fragment {
+			for j, letter := range row {
 				if partial.solution.Get(partial.curRow, j) != letter {
 					// Don't deduct if already set (from partial)
 					if remainingLetters[letter] > 0 {
@@ -299,7 +370,7 @@ func (s *solver) evaluateRow(ctx context.Context, board *entity.Board, partial p
 				availableLetters: remainingLetters,
 				wildcardCount:    cur.wildcardCount,
 				curRow:           partial.curRow + 1,
-			}, solutions)
+			}, overallBest, solutions)
 			score, err := s.scorer.Score(ctx, board, candidates[0])
 			if err != nil {
 				// swallow error and continue
@@ -311,7 +382,7 @@ func (s *solver) evaluateRow(ctx context.Context, board *entity.Board, partial p
 			} else if score >= bestScore {
 				best = candidates
 				bestScore = score
-				if partial.curRow == entity.BoardSize-1 {
+				if partial.curRow == board.Size.Rows-1 {
 					for _, candidate := range candidates {
 						solutions <- candidateSolution{
 							solution: candidate,
@@ -327,7 +398,7 @@ func (s *solver) evaluateRow(ctx context.Context, board *entity.Board, partial p
 }
 
 func (s *solver) getTheoreticalMax(ctx context.Context, board *entity.Board, partial partialSolution) int {
-	potentialSolution := slices.Clone(partial.solution)
+	potentialSolution := partial.solution.Clone()
 
 	// Count what's already set in partial
 	score, err := s.scorer.Score(ctx, board, potentialSolution)
@@ -370,6 +441,9 @@ func (s *solver) getTheoreticalMax(ctx context.Context, board *entity.Board, par
 	remainingLetters := maps.Clone(partial.availableLetters)
 	for _, multiplier := range multiplierCoords {
 		for ; ; tileIdx++ {
+			if tileIdx >= len(sortedTiles) {
+				break
+			}
 			if remainingLetters[sortedTiles[tileIdx]] < 1 {
 				continue
 			}
@@ -384,8 +458,8 @@ func (s *solver) getTheoreticalMax(ctx context.Context, board *entity.Board, par
 	}
 
 	// Place remaining letters
-	for row := partial.curRow; row < entity.BoardSize; row++ {
-		for col := 0; col < entity.BoardSize; col++ {
+	for row := partial.curRow; row < board.Size.Rows; row++ {
+		for col := 0; col < board.Size.Cols; col++ {
 			if potentialSolution.Get(row, col) != ' ' {
 				continue
 			}
@@ -408,13 +482,13 @@ func (s *solver) getTheoreticalMax(ctx context.Context, board *entity.Board, par
 	}
 
 	// Count rows
-	for row := partial.curRow; row < entity.BoardSize; row++ {
+	for row := partial.curRow; row < board.Size.Rows; row++ {
 		rowScore := 0
 		for col, letter := range potentialSolution.GetRow(row) {
 			rowScore += board.Tiles[letter].Value * board.Multipliers[row][col]
 		}
 		// Fudge by 1 for rounding
-		score += int(math.Ceil(float64(rowScore)*entity.CommonMultiplier)) + 1
+		score += int(math.Ceil(float64(rowScore)*s.rules.CommonMultiplier)) + 1
 	}
 
 	// Count bonus word if needed
@@ -425,7 +499,7 @@ func (s *solver) getTheoreticalMax(ctx context.Context, board *entity.Board, par
 			bonusScore += board.Tiles[letter].Value * board.Multipliers[bonusCoord[0]][bonusCoord[1]]
 		}
 		// Fudge by 1 for rounding
-		score += int(math.Ceil(float64(bonusScore)*entity.CommonMultiplier)) + 1
+		score += int(math.Ceil(float64(bonusScore)*s.rules.CommonMultiplier)) + 1
 	}
 
 	return score
@@ -0,0 +1,137 @@
+package solver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azhu2/bongo/src/controller/scorer"
+	"github.com/azhu2/bongo/src/controller/wordlist"
+	"github.com/azhu2/bongo/src/entity"
+	"github.com/azhu2/bongo/testdata"
+)
+
+var tinyWords = testdata.StaticWordList{"ACT", "ATE", "BAT", "CAT", "EAT", "TAB", "TEA"}
+
+// 3x3 board with a vertical bonus word down the first column
+var tinyBoard = &entity.Board{
+	Size: entity.Size{Rows: 3, Cols: 3},
+	Tiles: map[rune]entity.Tile{
+		'C': {Value: 30, Count: 1},
+		'B': {Value: 20, Count: 1},
+		'E': {Value: 2, Count: 1},
+		'T': {Value: 5, Count: 3},
+		'A': {Value: 1, Count: 3},
+	},
+	Multipliers: [][]int{
+		{1, 1, 2},
+		{1, 1, 1},
+		{3, 1, 1},
+	},
+	BonusWord: [][]int{{0, 0}, {1, 0}, {2, 0}},
+}
+
+// newSolver builds a solver and scorer over words with the default rules
+func newSolver(t *testing.T, words testdata.StaticWordList) (Controller, scorer.Controller) {
+	ctx := context.Background()
+	wordlistBuilder, err := wordlist.New(wordlist.Params{Rules: entity.DefaultRules(), Importer: words})
+	require.NoError(t, err)
+	list, err := wordlistBuilder.BuildWordList(ctx)
+	require.NoError(t, err)
+	scorerResult, err := scorer.New(scorer.Params{Rules: entity.DefaultRules(), WordList: list})
+	require.NoError(t, err)
+	solverResult, err := New(Params{Rules: entity.DefaultRules(), Scorer: scorerResult.Controller, WordList: list})
+	require.NoError(t, err)
+	return solverResult.Controller, scorerResult.Controller
+}
+
+func TestSolve_TinyBoard(t *testing.T) {
+	ctx := context.Background()
+	solverController, scorerController := newSolver(t, tinyWords)
+
+	solutions, err := solverController.Solve(ctx, tinyBoard)
+	require.NoError(t, err)
+	require.NotEmpty(t, solutions)
+	for _, solution := range solutions {
+		assert.Equal(t, tinyBoard.Size, solution.Size)
+	}
+	score, err := scorerController.Score(ctx, tinyBoard, solutions[0])
+	require.NoError(t, err)
+
+	// Small enough to check every combination of rows
+	best := 0
+	for _, first := range tinyWords {
+		for _, second := range tinyWords {
+			for _, third := range tinyWords {
+				candidate := entity.NewSolution(tinyBoard.Size, first+second+third)
+				candidateScore, err := scorerController.Score(ctx, tinyBoard, candidate)
+				if err != nil {
+					continue
+				}
+				best = max(best, candidateScore)
+			}
+		}
+	}
+	assert.Equal(t, best, score)
+}
+
+func TestSolve_LeadingBlank(t *testing.T) {
+	ctx := context.Background()
+	solverController, _ := newSolver(t, testdata.StaticWordList{"ABC", "ABCD"})
+	// Fewer tiles than cells, so the theoretical max runs out of tiles to place
+	board := &entity.Board{
+		Size: entity.Size{Rows: 1, Cols: 4},
+		Tiles: map[rune]entity.Tile{
+			'A': {Value: 5, Count: 1},
+			'B': {Value: 5, Count: 1},
+			'C': {Value: 10, Count: 1},
+		},
+		Multipliers: [][]int{{2, 1, 1, 1}},
+		BonusWord:   [][]int{{0, 1}, {0, 2}, {0, 3}},
+	}
+
+	solutions, err := solverController.Solve(ctx, board)
+	require.NoError(t, err)
+	require.NotEmpty(t, solutions)
+	assert.Equal(t, entity.NewSolution(board.Size, " ABC"), solutions[0])
+}
+
+func TestNew_MultipleWordsPerRow(t *testing.T) {
+	rules := entity.DefaultRules()
+	rules.OneWordPerRow = false
+	_, err := New(Params{Rules: rules})
+	assert.ErrorContains(t, err, "one word per row")
+}
+
+func TestSolve_Reused(t *testing.T) {
+	ctx := context.Background()
+	solverController, _ := newSolver(t, tinyWords)
+	_, err := solverController.Solve(ctx, tinyBoard)
+	require.NoError(t, err)
+
+	// The same board with every tile worth less, so its best is below the last one's
+	cheaper := *tinyBoard
+	cheaper.Tiles = map[rune]entity.Tile{}
+	for letter, tile := range tinyBoard.Tiles {
+		cheaper.Tiles[letter] = entity.Tile{Value: 1, Count: tile.Count}
+	}
+	solutions, err := solverController.Solve(ctx, &cheaper)
+	require.NoError(t, err)
+	assert.NotEmpty(t, solutions)
+}
+
+func TestSolve_NoSolution(t *testing.T) {
+	ctx := context.Background()
+	solverController, _ := newSolver(t, testdata.StaticWordList{"ABC"})
+	board := &entity.Board{
+		Size:        entity.Size{Rows: 1, Cols: 3},
+		Tiles:       map[rune]entity.Tile{'X': {Value: 1, Count: 3}},
+		Multipliers: [][]int{{1, 1, 1}},
+		BonusWord:   [][]int{{0, 0}, {0, 1}, {0, 2}},
+	}
+
+	_, err := solverController.Solve(ctx, board)
+	assert.ErrorIs(t, err, ErrNoSolution)
+}
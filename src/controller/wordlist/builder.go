@@ -4,28 +4,56 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"maps"
 	"slices"
+	"strings"
 
 	"go.uber.org/fx"
 
 	"github.com/azhu2/bongo/src/entity"
+	"github.com/azhu2/bongo/src/gateway/overrides"
+	"github.com/azhu2/bongo/src/gateway/wordlistcache"
 	"github.com/azhu2/bongo/src/gateway/wordlistimporter"
 )
 
 var Module = fx.Module("wordlist",
 	wordlistimporter.Module,
+	wordlistcache.Module,
+	overrides.Module,
 	fx.Provide(New),
 )
 
 type Controller interface {
 	BuildWordList(ctx context.Context) (*entity.WordList, error)
+	// Status explains whether a word makes it into the list and why
+	Status(ctx context.Context, word string) (*WordStatus, error)
+	// SetOverride records a change to the base list, replacing any it contradicts
+	SetOverride(ctx context.Context, word string, action entity.OverrideAction) error
+	// ResetOverride drops every change to a word, so it is as the base list has it
+	ResetOverride(ctx context.Context, word string) error
+}
+
+type WordStatus struct {
+	Word   string
+	InBase bool
+	// Membership and Commonness are the overrides that apply, or "" for none
+	Membership entity.OverrideAction
+	Commonness entity.OverrideAction
+	// Playable is whether the rules allow a word this long
+	Playable bool
+	// InList and Common are the outcome
+	InList bool
+	Common bool
 }
 
 type Params struct {
 	fx.In
 
+	Rules    entity.Rules
 	Importer wordlistimporter.Gateway
+	// Cache skips rebuilding a list whose words and rules have not changed. Optional.
+	Cache wordlistcache.Gateway `optional:"true"`
+	// Overrides are the user's changes to the base list. Optional.
+	Overrides overrides.Gateway `optional:"true"`
 }
 
 type Result struct {
@@ -35,87 +63,274 @@ type Result struct {
 }
 
 type controller struct {
-	importer wordlistimporter.Gateway
+	rules     entity.Rules
+	importer  wordlistimporter.Gateway
+	cache     wordlistcache.Gateway
+	overrides overrides.Gateway
 }
 
 func New(p Params) (Result, error) {
 	return Result{
 		Controller: &controller{
-			importer: p.Importer,
+			rules:     p.Rules,
+			importer:  p.Importer,
+			cache:     p.Cache,
+			overrides: p.Overrides,
 		},
 	}, nil
 }
 
 func (c *controller) BuildWordList(ctx context.Context) (*entity.WordList, error) {
-	wordList, err := c.importer.ImportWordList(ctx)
+	wordList, err := c.entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.cache == nil {
+		return c.buildWordList(wordList), nil
+	}
+
+	// A bad cache only costs a rebuild, so its errors are logged rather than returned
+	key := cacheKey(c.rules, wordList)
+	if raw, ok, err := c.cache.Get(ctx, key); err != nil {
+		slog.Warn("unable to read cached word list", "err", err)
+	} else if ok {
+		cached, err := decodeWordList(raw)
+		if err == nil {
+			slog.Debug("loaded cached word list", "key", key)
+			return cached, nil
+		}
+		slog.Warn("ignoring corrupt cached word list", "key", key, "err", err)
+	}
+
+	built := c.buildWordList(wordList)
+	if err := c.cache.Put(ctx, key, encodeWordList(built)); err != nil {
+		slog.Warn("unable to cache word list", "err", err)
+	}
+	return built, nil
+}
+
+// entry is a word to build into the list
+type entry struct {
+	word   string
+	common bool
+}
+
+// entries applies the overrides to the base list, sorted by word. Base and added words are
+// common unless marked otherwise.
+func (c *controller) entries(ctx context.Context) ([]entry, error) {
+	base, report, err := c.importer.ImportWordList(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("could not import word list %w", err)
 	}
+	logImportReport(report)
+	userOverrides, err := c.loadOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	root := entity.DAGNode{
-		Fragment: []rune{},
-		Children: make(map[rune]*entity.DAGNode),
+	words := slices.Clone(base)
+	for _, override := range userOverrides {
+		words = append(words, override.Word)
+	}
+	slices.Sort(words)
+	words = slices.Compact(words)
+	inBase := make(map[string]bool, len(base))
+	for _, word := range base {
+		inBase[word] = true
 	}
-	nodeMap := make(map[int]map[rune][]*entity.DAGNode)
 
-	for _, word := range wordList {
-		node := &root
-		stack := entity.Stack[*entity.DAGNode]{}
-
-		for i, letter := range word {
-			if child, ok := node.Children[letter]; ok {
-				// Move current pointer to existing child node
-				node = child
-			} else {
-				// Create new child node
-				child := entity.DAGNode{
-					Fragment: append(slices.Clone(node.Fragment), letter),
-					Children: make(map[rune]*entity.DAGNode),
-				}
-				node.Children[letter] = &child
-				node = &child
-
-				// Add to node map
-				var mapEntry map[rune][]*entity.DAGNode
-				if mapEntry = nodeMap[i]; mapEntry == nil {
-					mapEntry = map[rune][]*entity.DAGNode{}
-				}
-				mapEntry[letter] = append(mapEntry[letter], &child)
-				nodeMap[i] = mapEntry
+	entries := make([]entry, 0, len(words))
+	for _, word := range words {
+		switch userOverrides.Membership(word) {
+		case entity.OverrideRemove:
+			continue
+		case "":
+			if !inBase[word] {
+				// Only marked common or uncommon
+				continue
 			}
-			stack.Push(node)
 		}
-		node.IsWord = true
-		// Add trailing empty nodes
-		for i := len(word); i < entity.BoardSize; i++ {
-			child := entity.DAGNode{
-				Fragment: append(slices.Clone(node.Fragment), ' '),
-				Children: make(map[rune]*entity.DAGNode),
-				IsWord:   true,
-			}
-			node.Children[' '] = &child
-			node = &child
+		entries = append(entries, entry{
+			word:   word,
+			common: userOverrides.Commonness(word) != entity.OverrideUncommon,
+		})
+	}
+	return entries, nil
+}
+
+// logImportReport counts rejected lines by reason. Invalid lines are listed too, since they
+// usually mean a malformed source; duplicates are expected when merging lists.
+func logImportReport(report *wordlistimporter.Report) {
+	counts := report.Counts()
+	slog.Debug("imported word list",
+		"rejected_invalid", counts[wordlistimporter.ReasonInvalid],
+		"rejected_duplicate", counts[wordlistimporter.ReasonDuplicate],
+	)
+	for _, rejection := range report.Rejections {
+		if rejection.Reason == wordlistimporter.ReasonInvalid {
+			slog.Debug("rejected word list line",
+				"source", rejection.Source,
+				"line", rejection.Line,
+				"text", rejection.Text,
+			)
+		}
+	}
+}
+
+func (c *controller) loadOverrides(ctx context.Context) (entity.Overrides, error) {
+	if c.overrides == nil {
+		return entity.Overrides{}, nil
+	}
+	userOverrides, err := c.overrides.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not load word list overrides %w", err)
+	}
+	return userOverrides, nil
+}
+
+func (c *controller) Status(ctx context.Context, word string) (*WordStatus, error) {
+	word, err := wordlistimporter.Normalize(word)
+	if err != nil {
+		return nil, err
+	}
+	base, _, err := c.importer.ImportWordList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not import word list %w", err)
+	}
+	userOverrides, err := c.loadOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := WordStatus{
+		Word:       word,
+		InBase:     slices.Contains(base, word),
+		Membership: userOverrides.Membership(word),
+		Commonness: userOverrides.Commonness(word),
+		Playable:   c.rules.IsPlayableLength(len(word)),
+	}
+	status.InList = status.Playable && status.Membership != entity.OverrideRemove &&
+		(status.InBase || status.Membership == entity.OverrideAdd)
+	status.Common = status.InList && status.Commonness != entity.OverrideUncommon
+	return &status, nil
+}
+
+func (c *controller) SetOverride(ctx context.Context, word string, action entity.OverrideAction) error {
+	word, err := wordlistimporter.Normalize(word)
+	if err != nil {
+		return err
+	}
+	return c.updateOverrides(ctx, func(userOverrides entity.Overrides) entity.Overrides {
+		return userOverrides.Set(word, action)
+	})
+}
+
+func (c *controller) ResetOverride(ctx context.Context, word string) error {
+	word, err := wordlistimporter.Normalize(word)
+	if err != nil {
+		return err
+	}
+	return c.updateOverrides(ctx, func(userOverrides entity.Overrides) entity.Overrides {
+		return userOverrides.Reset(word)
+	})
+}
+
+func (c *controller) updateOverrides(ctx context.Context, update func(entity.Overrides) entity.Overrides) error {
+	if c.overrides == nil {
+		return fmt.Errorf("no overrides file configured")
+	}
+	userOverrides, err := c.loadOverrides(ctx)
+	if err != nil {
+		return err
+	}
+	return c.overrides.Save(ctx, update(userOverrides))
+}
+
+// buildWordList builds a minimized DAWG incrementally from sorted words (Daciuk et al.). Once a
+// word is added, nodes past its common prefix with the next word can no longer change, so they
+// are swapped for an identical node already in the graph if there is one. Words aren't padded to
+// the board width; the solver places each one at every column offset it fits at.
+func (c *controller) buildWordList(wordList []entry) *entity.WordList {
+	words := []entry{}
+	skipped := 0
+	for _, word := range wordList {
+		if !c.rules.IsPlayableLength(len(word.word)) {
+			skipped++
+			continue
 		}
+		words = append(words, word)
 	}
 
-	// Add leading empty nodes - not updating nodeMap because bonus words
-	// will never be non-full so no need to backfill partials with leading spaces
-	for i := 0; i < entity.BoardSize-1; i++ {
-		oldRoot := root
-		children := maps.Clone(root.Children)
-		children[' '] = &oldRoot
-		newRoot := entity.DAGNode{
-			Fragment: []rune{' '},
-			Children: children,
-			IsWord:   false,
+	root := &entity.DAGNode{}
+	registry := nodeRegistry{nodes: map[string]*entity.DAGNode{}, ids: map[*entity.DAGNode]int{}}
+	// Nodes along the previous word that have not been minimized yet
+	unchecked := []*entity.DAGNode{root}
+	previous := []rune{}
+	for _, word := range words {
+		letters := []rune(word.word)
+		common := 0
+		for common < len(letters) && common < len(previous) && letters[common] == previous[common] {
+			common++
+		}
+		registry.minimize(&unchecked, common)
+
+		node := unchecked[len(unchecked)-1]
+		for _, letter := range letters[common:] {
+			child := &entity.DAGNode{}
+			// Words are sorted, so edges are appended in order
+			node.Edges = append(node.Edges, entity.DAGEdge{Letter: letter, Node: child})
+			unchecked = append(unchecked, child)
+			node = child
 		}
-		root = newRoot
+		node.IsWord = true
+		node.IsCommon = word.common
+		previous = letters
 	}
+	registry.minimize(&unchecked, 0)
 
-	slog.Debug("processed words into DAG")
+	slog.Debug("processed words into DAWG",
+		"word_count", len(words),
+		"node_count", len(registry.nodes)+1,
+		"skipped_length", skipped,
+	)
 
-	return &entity.WordList{
-		Root:    &root,
-		NodeMap: nodeMap,
-	}, nil
+	return entity.NewWordList(root)
+}
+
+// nodeRegistry holds one node for each distinct subgraph seen so far
+type nodeRegistry struct {
+	nodes map[string]*entity.DAGNode
+	ids   map[*entity.DAGNode]int
+}
+
+// minimize replaces unchecked nodes deeper than depth with equivalent registered ones
+func (r *nodeRegistry) minimize(unchecked *[]*entity.DAGNode, depth int) {
+	for len(*unchecked) > depth+1 {
+		last := len(*unchecked) - 1
+		node, parent := (*unchecked)[last], (*unchecked)[last-1]
+		key := r.key(node)
+		if existing, ok := r.nodes[key]; ok {
+			parent.Edges[len(parent.Edges)-1].Node = existing
+		} else {
+			r.nodes[key] = node
+			r.ids[node] = len(r.ids)
+		}
+		*unchecked = (*unchecked)[:last]
+	}
+}
+
+// key identifies a node by whether it ends a word and where its edges go. Children are always
+// registered first, so their ids are enough to tell subgraphs apart.
+func (r *nodeRegistry) key(node *entity.DAGNode) string {
+	var key strings.Builder
+	if node.IsWord {
+		key.WriteByte('!')
+	}
+	if node.IsCommon {
+		key.WriteByte('*')
+	}
+	for _, edge := range node.Edges {
+		fmt.Fprintf(&key, "%c%d,", edge.Letter, r.ids[edge.Node])
+	}
+	return key.String()
 }
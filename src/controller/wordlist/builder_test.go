@@ -7,26 +7,23 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/azhu2/bongo/src/gateway/wordlistimporter"
+	"github.com/azhu2/bongo/src/entity"
+	"github.com/azhu2/bongo/testdata"
 )
 
 func TestScore(t *testing.T) {
 	ctx := context.Background()
-	importerGateway, err := wordlistimporter.New()
-	require.NoError(t, err)
-	wordlistBuilder, err := New(Params{Importer: importerGateway.Gateway})
+	wordlistBuilder, err := New(Params{Rules: entity.DefaultRules(), Importer: testdata.StaticWordList{"ACT", "BACK", "CRAB"}})
 	require.NoError(t, err)
 
 	list, err := wordlistBuilder.BuildWordList(ctx)
 	assert.NoError(t, err)
-	assert.Contains(t, list.Root.Children, 'A')
+	assert.NotNil(t, list.Root.Child('A'))
 }
 
 func TestScore_TraverseWord(t *testing.T) {
 	ctx := context.Background()
-	importerGateway, err := wordlistimporter.New()
-	require.NoError(t, err)
-	wordlistBuilder, err := New(Params{Importer: importerGateway.Gateway})
+	wordlistBuilder, err := New(Params{Rules: entity.DefaultRules(), Importer: testdata.StaticWordList{"CRAB", "CRABS", "CRAM", "SCRAB"}})
 	require.NoError(t, err)
 
 	list, err := wordlistBuilder.BuildWordList(ctx)
@@ -34,71 +31,126 @@ func TestScore_TraverseWord(t *testing.T) {
 
 	// Check a specific word CRAB
 	node := list.Root
-
-	node = node.Children['C']
-	assert.NotEmpty(t, node)
-	assert.Equal(t, []rune{'C'}, node.Fragment)
-	assert.False(t, node.IsWord)
-	assert.Contains(t, list.NodeMap[0]['C'], node)
-
-	node = node.Children['R']
-	assert.NotEmpty(t, node)
-	assert.Equal(t, []rune{'C', 'R'}, node.Fragment)
-	assert.False(t, node.IsWord)
-	assert.Contains(t, list.NodeMap[1]['R'], node)
-
-	node = node.Children['A']
-	assert.NotEmpty(t, node)
-	assert.Equal(t, []rune{'C', 'R', 'A'}, node.Fragment)
-	assert.False(t, node.IsWord)
-	assert.Contains(t, list.NodeMap[2]['A'], node)
-
-	node = node.Children['B']
-	assert.NotEmpty(t, node)
-	assert.Equal(t, []rune{'C', 'R', 'A', 'B'}, node.Fragment)
+	for i, letter := range "CRA" {
+		node = node.Child(letter)
+		require.NotNil(t, node)
+		assert.False(t, node.IsWord)
+		assert.Contains(t, list.Prefixes(i, letter), entity.Prefix{Letters: []rune("CRAB")[:i+1], Node: node})
+	}
+	node = node.Child('B')
+	require.NotNil(t, node)
 	assert.True(t, node.IsWord)
-	assert.Contains(t, list.NodeMap[3]['B'], node)
+	assert.Contains(t, list.Prefixes(3, 'B'), entity.Prefix{Letters: []rune("CRAB"), Node: node})
 
-	node = node.Children[' ']
-	assert.NotEmpty(t, node, "should have trailing empty node")
-	assert.Equal(t, []rune{'C', 'R', 'A', 'B', ' '}, node.Fragment)
-	assert.True(t, node.IsWord)
+	assert.Nil(t, node.Child(' '), "should not pad with trailing empty node")
+	assert.Nil(t, node.Child('X'))
 }
 
-func TestScore_TraverseWordWithLeadingSpace(t *testing.T) {
+func TestScore_NoLeadingSpace(t *testing.T) {
 	ctx := context.Background()
-	importerGateway, err := wordlistimporter.New()
+	wordlistBuilder, err := New(Params{Rules: entity.DefaultRules(), Importer: testdata.StaticWordList{"BAT", "CAT", "TAB"}})
 	require.NoError(t, err)
-	wordlistBuilder, err := New(Params{Importer: importerGateway.Gateway})
+
+	list, err := wordlistBuilder.BuildWordList(ctx)
+	require.NoError(t, err)
+
+	// Padding depends on board width, so the list itself has none
+	assert.Nil(t, list.Root.Child(' '))
+	for col := range entity.DefaultRules().MaxWordLength {
+		assert.Empty(t, list.Prefixes(col, ' '))
+	}
+}
+
+func TestBuildWordList_SharedSuffixes(t *testing.T) {
+	ctx := context.Background()
+	words := testdata.StaticWordList{"CATS", "BATS", "BAT", "CAT", "HATS", "HAT", "HAS", "AT"}
+	wordlistBuilder, err := New(Params{Rules: entity.DefaultRules(), Importer: words})
 	require.NoError(t, err)
 
 	list, err := wordlistBuilder.BuildWordList(ctx)
 	require.NoError(t, err)
 
-	// Check CRAB again with leading space
-	node := list.Root
-	node = node.Children[' ']
-	assert.NotEmpty(t, node)
-	assert.Equal(t, []rune{' '}, node.Fragment)
-	assert.False(t, node.IsWord)
-
-	node = node.Children['C']
-	assert.NotEmpty(t, node)
-	assert.Equal(t, []rune{'C'}, node.Fragment)
-	assert.False(t, node.IsWord)
-
-	node = node.Children['R']
-	assert.NotEmpty(t, node)
-	assert.Equal(t, []rune{'C', 'R'}, node.Fragment)
-	assert.False(t, node.IsWord)
-
-	node = node.Children['A']
-	assert.NotEmpty(t, node)
-	assert.Equal(t, []rune{'C', 'R', 'A'}, node.Fragment)
-	assert.False(t, node.IsWord)
-
-	node = node.Children['B']
-	assert.NotEmpty(t, node)
-	assert.Equal(t, []rune{'C', 'R', 'A', 'B'}, node.Fragment)
-	assert.True(t, node.IsWord)
+	// BAT(S) and CAT(S) end the same way, so they share everything after the first letter
+	assert.Same(t, list.Root.Child('B').Child('A'), list.Root.Child('C').Child('A'))
+	// HA has an extra S branch, so it can't be shared
+	assert.NotSame(t, list.Root.Child('B').Child('A'), list.Root.Child('H').Child('A'))
+	assert.Same(t, list.Root.Child('B').Child('A').Child('T'), list.Root.Child('H').Child('A').Child('T'))
+	// AT is too short for the rules
+	assert.Nil(t, list.Root.Child('A'))
+
+	assert.ElementsMatch(t, []string{"BAT", "BATS", "CAT", "CATS", "HAS", "HAT", "HATS"}, list.Words())
+	prefixes := []string{}
+	for _, prefix := range list.Prefixes(1, 'A') {
+		prefixes = append(prefixes, string(prefix.Letters))
+	}
+	assert.Equal(t, []string{"BA", "CA", "HA"}, prefixes)
+}
+
+type memoryOverrides struct {
+	overrides entity.Overrides
+}
+
+func (m *memoryOverrides) Load(_ context.Context) (entity.Overrides, error) {
+	return m.overrides, nil
+}
+
+func (m *memoryOverrides) Save(_ context.Context, overrides entity.Overrides) error {
+	m.overrides = overrides
+	return nil
+}
+
+func (m *memoryOverrides) Path() string {
+	return "memory"
+}
+
+func TestBuildWordList_Overrides(t *testing.T) {
+	ctx := context.Background()
+	userOverrides := &memoryOverrides{}
+	wordlistBuilder, err := New(Params{
+		Rules:     entity.DefaultRules(),
+		Importer:  testdata.StaticWordList{"BAT", "CAT", "HAT"},
+		Overrides: userOverrides,
+	})
+	require.NoError(t, err)
+	c := wordlistBuilder.Controller
+
+	require.NoError(t, c.SetOverride(ctx, "qoph", entity.OverrideAdd))
+	require.NoError(t, c.SetOverride(ctx, "QOPH", entity.OverrideUncommon))
+	require.NoError(t, c.SetOverride(ctx, "CAT", entity.OverrideRemove))
+	require.NoError(t, c.SetOverride(ctx, "HAT", entity.OverrideUncommon))
+	// Marking a word not in the list doesn't add it
+	require.NoError(t, c.SetOverride(ctx, "EMU", entity.OverrideCommon))
+
+	list, err := c.BuildWordList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BAT", "HAT", "QOPH"}, list.Words())
+	assert.True(t, list.IsCommon("BAT"))
+	assert.False(t, list.IsCommon("HAT"))
+	assert.False(t, list.IsCommon("QOPH"))
+	// BAT and HAT share a suffix but not commonness
+	assert.NotSame(t, list.Root.Child('B').Child('A'), list.Root.Child('H').Child('A'))
+
+	status, err := c.Status(ctx, "qoph")
+	require.NoError(t, err)
+	assert.Equal(t, &WordStatus{
+		Word:       "QOPH",
+		Membership: entity.OverrideAdd,
+		Commonness: entity.OverrideUncommon,
+		Playable:   true,
+		InList:     true,
+	}, status)
+
+	// A later add replaces the remove, and reset goes back to the base list
+	require.NoError(t, c.SetOverride(ctx, "CAT", entity.OverrideAdd))
+	require.NoError(t, c.ResetOverride(ctx, "HAT"))
+	assert.Equal(t, entity.Overrides{
+		{Action: entity.OverrideAdd, Word: "QOPH"},
+		{Action: entity.OverrideUncommon, Word: "QOPH"},
+		{Action: entity.OverrideCommon, Word: "EMU"},
+		{Action: entity.OverrideAdd, Word: "CAT"},
+	}, userOverrides.overrides)
+	list, err = c.BuildWordList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BAT", "CAT", "HAT", "QOPH"}, list.Words())
+	assert.True(t, list.IsCommon("HAT"))
 }
@@ -0,0 +1,125 @@
+package wordlist
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/azhu2/bongo/src/entity"
+)
+
+// Bump codecVersion whenever the encoding or the way lists are built changes,
+// so that stale caches are rebuilt instead of misread
+const (
+	codecMagic   = "BONGODAG"
+	codecVersion = 3
+)
+
+const (
+	flagWord = 1 << iota
+	flagCommon
+)
+
+// cacheKey identifies a built word list by everything that goes into building it
+func cacheKey(rules entity.Rules, words []entry) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %d %d %d\n", codecMagic, codecVersion, rules.MinWordLength, rules.MaxWordLength)
+	for _, word := range words {
+		fmt.Fprintf(hash, "%s %t\n", word.word, word.common)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// encodeWordList writes each distinct node once, children before parents, so the root is last.
+// A node is its flags (1 for IsWord, 2 for IsCommon) and edge count, followed by each edge's letter and the index of the
+// node it leads to, all as uvarints.
+func encodeWordList(wordList *entity.WordList) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(codecMagic)
+	buf.WriteByte(codecVersion)
+
+	ids := map[*entity.DAGNode]uint64{}
+	nodes := []*entity.DAGNode{}
+	var number func(node *entity.DAGNode)
+	number = func(node *entity.DAGNode) {
+		if _, ok := ids[node]; ok {
+			return
+		}
+		for _, edge := range node.Edges {
+			number(edge.Node)
+		}
+		ids[node] = uint64(len(nodes))
+		nodes = append(nodes, node)
+	}
+	number(wordList.Root)
+
+	buf.Write(binary.AppendUvarint(nil, uint64(len(nodes))))
+	for _, node := range nodes {
+		flags := uint64(0)
+		if node.IsWord {
+			flags |= flagWord
+		}
+		if node.IsCommon {
+			flags |= flagCommon
+		}
+		buf.Write(binary.AppendUvarint(nil, flags))
+		buf.Write(binary.AppendUvarint(nil, uint64(len(node.Edges))))
+		for _, edge := range node.Edges {
+			buf.Write(binary.AppendUvarint(nil, uint64(edge.Letter)))
+			buf.Write(binary.AppendUvarint(nil, ids[edge.Node]))
+		}
+	}
+	return buf.Bytes()
+}
+
+func decodeWordList(data []byte) (*entity.WordList, error) {
+	reader := bufio.NewReader(bytes.NewReader(data))
+	header := make([]byte, len(codecMagic)+1)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, fmt.Errorf("unable to read word list header %w", err)
+	}
+	if string(header[:len(codecMagic)]) != codecMagic || header[len(codecMagic)] != codecVersion {
+		return nil, fmt.Errorf("unexpected word list header %q", header)
+	}
+
+	nodeCount, err := binary.ReadUvarint(reader)
+	if err != nil || nodeCount == 0 || nodeCount > uint64(len(data)) {
+		return nil, fmt.Errorf("unable to read word list node count")
+	}
+	nodes := make([]*entity.DAGNode, 0, nodeCount)
+	for range nodeCount {
+		flags, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read word list node %w", err)
+		}
+		edgeCount, err := binary.ReadUvarint(reader)
+		if err != nil || edgeCount > uint64(len(data)) {
+			return nil, fmt.Errorf("unable to read word list node edges")
+		}
+		node := &entity.DAGNode{IsWord: flags&flagWord != 0, IsCommon: flags&flagCommon != 0}
+		if edgeCount > 0 {
+			node.Edges = make([]entity.DAGEdge, edgeCount)
+		}
+		for i := range node.Edges {
+			letter, err := binary.ReadUvarint(reader)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read word list letter %w", err)
+			}
+			// Children always come first, which also rules out cycles
+			id, err := binary.ReadUvarint(reader)
+			if err != nil || id >= uint64(len(nodes)) {
+				return nil, fmt.Errorf("unable to read word list edge")
+			}
+			node.Edges[i] = entity.DAGEdge{Letter: rune(letter), Node: nodes[id]}
+		}
+		nodes = append(nodes, node)
+	}
+	if _, err := reader.ReadByte(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected trailing data in word list")
+	}
+	return entity.NewWordList(nodes[len(nodes)-1]), nil
+}
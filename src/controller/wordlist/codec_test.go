@@ -0,0 +1,97 @@
+package wordlist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azhu2/bongo/src/entity"
+	"github.com/azhu2/bongo/testdata"
+)
+
+type memoryCache map[string][]byte
+
+func (m memoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	raw, ok := m[key]
+	return raw, ok, nil
+}
+
+func (m memoryCache) Put(_ context.Context, key string, data []byte) error {
+	m[key] = data
+	return nil
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	builder, err := New(Params{Rules: entity.DefaultRules(), Importer: testdata.StaticWordList(testdata.Words)})
+	require.NoError(t, err)
+	list, err := builder.BuildWordList(ctx)
+	require.NoError(t, err)
+
+	decoded, err := decodeWordList(encodeWordList(list))
+	require.NoError(t, err)
+	assert.Equal(t, list.Root, decoded.Root)
+	assert.Equal(t, list.Words(), decoded.Words())
+	// Shared nodes stay shared instead of being copied for each parent
+	assert.Same(t, decoded.Root.Child('S').Child('H').Child('A').Child('M').Child('E'),
+		decoded.Root.Child('P').Child('L').Child('A').Child('N').Child('E'))
+	assert.Equal(t, list.Prefixes(2, 'A'), decoded.Prefixes(2, 'A'))
+
+	_, err = decodeWordList([]byte("BONGODAG"))
+	assert.Error(t, err, "truncated")
+	_, err = decodeWordList(append(encodeWordList(list), 0))
+	assert.Error(t, err, "trailing data")
+}
+
+func TestBuildWordList_Cache(t *testing.T) {
+	ctx := context.Background()
+	cache := memoryCache{}
+	words := testdata.StaticWordList{"CRAB", "BACK", "LAMBS"}
+	builder, err := New(Params{Rules: entity.DefaultRules(), Importer: words, Cache: cache})
+	require.NoError(t, err)
+
+	built, err := builder.BuildWordList(ctx)
+	require.NoError(t, err)
+	require.Len(t, cache, 1)
+
+	cached, err := builder.BuildWordList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, built.Root, cached.Root)
+	assert.Equal(t, built.Words(), cached.Words())
+
+	// Different rules build a different list
+	rules := entity.DefaultRules()
+	rules.MaxWordLength = 4
+	builder, err = New(Params{Rules: rules, Importer: words, Cache: cache})
+	require.NoError(t, err)
+	_, err = builder.BuildWordList(ctx)
+	require.NoError(t, err)
+	assert.Len(t, cache, 2)
+
+	// Overrides change the list, so they get their own entry
+	builder, err = New(Params{
+		Rules:     rules,
+		Importer:  words,
+		Cache:     cache,
+		Overrides: &memoryOverrides{overrides: entity.Overrides{{Action: entity.OverrideUncommon, Word: "CRAB"}}},
+	})
+	require.NoError(t, err)
+	uncommon, err := builder.BuildWordList(ctx)
+	require.NoError(t, err)
+	assert.Len(t, cache, 3)
+	assert.False(t, uncommon.IsCommon("CRAB"))
+	decoded, err := decodeWordList(encodeWordList(uncommon))
+	require.NoError(t, err)
+	assert.False(t, decoded.IsCommon("CRAB"))
+	assert.True(t, decoded.IsCommon("BACK"))
+
+	// Corrupt entries are rebuilt and overwritten
+	for key := range cache {
+		cache[key] = []byte("garbage")
+	}
+	rebuilt, err := builder.BuildWordList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BACK", "CRAB"}, rebuilt.Words(), "LAMBS is too long")
+}
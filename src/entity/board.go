@@ -5,13 +5,11 @@ import (
 	"slices"
 )
 
-const (
-	BoardSize        = 5
-	MaxWildcards     = 1
-	CommonMultiplier = 1.3
-)
+// DefaultSize is the size of the daily Puzzmo board
+var DefaultSize = Size{Rows: 5, Cols: 5}
 
 type Board struct {
+	Size        Size
 	Tiles       map[rune]Tile
 	Multipliers [][]int // Grid of multipliers
 	BonusWord   [][]int // Slice of [row,col] coords
@@ -19,6 +17,31 @@ type Board struct {
 	sortedTiles []rune
 }
 
+type Size struct {
+	Rows int
+	Cols int
+}
+
+func (s Size) Cells() int {
+	return s.Rows * s.Cols
+}
+
+func (s Size) Contains(row, col int) bool {
+	return row >= 0 && row < s.Rows && col >= 0 && col < s.Cols
+}
+
+// EmptyMultipliers is a grid of 1s
+func (s Size) EmptyMultipliers() [][]int {
+	multipliers := make([][]int, s.Rows)
+	for i := 0; i < s.Rows; i++ {
+		multipliers[i] = make([]int, s.Cols)
+		for j := 0; j < s.Cols; j++ {
+			multipliers[i][j] = 1
+		}
+	}
+	return multipliers
+}
+
 type Tile struct {
 	Value int
 	Count int
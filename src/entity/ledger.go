@@ -0,0 +1,238 @@
+package entity
+
+import "slices"
+
+// Cell is a (row, col) position with (0,0) as top left
+type Cell struct {
+	Row int
+	Col int
+}
+
+// TileRef identifies the physical tile a cell consumed
+type TileRef struct {
+	Letter rune
+	// Instance is which of the board's tiles for Letter, numbered in reading order. -1 for a wildcard.
+	Instance int
+	Wildcard bool
+}
+
+// Ledger tracks which tile each filled cell of a board consumed. Letters beyond a tile's count are
+// covered by wildcards until the rules' limit runs out.
+type Ledger struct {
+	board        *Board
+	maxWildcards int
+
+	// Indexed by row*cols+col. Empty cells are 0.
+	letters   []rune
+	wildcards []bool
+	// Wildcards the player chose explicitly, which are never moved
+	fixed []bool
+	// Cell indexes in placement order for undo
+	history []int
+	// Regular (non-wildcard) tiles in use per letter
+	used          map[rune]int
+	wildcardCount int
+}
+
+func NewLedger(board *Board, maxWildcards int) *Ledger {
+	return &Ledger{
+		board:        board,
+		maxWildcards: maxWildcards,
+		letters:      make([]rune, board.Size.Cells()),
+		wildcards:    make([]bool, board.Size.Cells()),
+		fixed:        make([]bool, board.Size.Cells()),
+		used:         map[rune]int{},
+	}
+}
+
+// Place records a letter played in a cell. It uses a tile if one is left, otherwise a wildcard.
+// Returns false without recording anything if the cell is filled or neither is available.
+func (l *Ledger) Place(cell Cell, letter rune) (TileRef, bool) {
+	idx := l.index(cell)
+	if l.letters[idx] != 0 {
+		return TileRef{}, false
+	}
+	if l.Remaining(letter) > 0 {
+		l.used[letter]++
+	} else if l.wildcardCount < l.maxWildcards {
+		l.wildcards[idx] = true
+		l.wildcardCount++
+	} else {
+		return TileRef{}, false
+	}
+	l.letters[idx] = letter
+	l.history = append(l.history, idx)
+	return l.Tile(cell)
+}
+
+// PlaceWildcard records a letter played with a wildcard even if a tile is left for it.
+// Returns false without recording anything if the cell is filled or no wildcards are left.
+func (l *Ledger) PlaceWildcard(cell Cell, letter rune) (TileRef, bool) {
+	idx := l.index(cell)
+	if l.letters[idx] != 0 || l.wildcardCount >= l.maxWildcards {
+		return TileRef{}, false
+	}
+	l.wildcards[idx] = true
+	l.fixed[idx] = true
+	l.wildcardCount++
+	l.letters[idx] = letter
+	l.history = append(l.history, idx)
+	return l.Tile(cell)
+}
+
+// Undo removes the most recent placement
+func (l *Ledger) Undo() (Cell, rune, bool) {
+	if len(l.history) == 0 {
+		return Cell{}, 0, false
+	}
+	idx := l.history[len(l.history)-1]
+	l.history = l.history[:len(l.history)-1]
+	letter := l.letters[idx]
+	l.letters[idx] = 0
+
+	if l.wildcards[idx] {
+		l.wildcards[idx] = false
+		l.fixed[idx] = false
+		l.wildcardCount--
+	} else {
+		l.used[letter]--
+		// A wildcard standing in for the same letter can take the freed tile instead
+		for other, otherLetter := range l.letters {
+			if otherLetter == letter && l.wildcards[other] && !l.fixed[other] {
+				l.wildcards[other] = false
+				l.wildcardCount--
+				l.used[letter]++
+				break
+			}
+		}
+	}
+	return l.cell(idx), letter, true
+}
+
+// Remaining is how many tiles of a letter are unused
+func (l *Ledger) Remaining(letter rune) int {
+	return l.board.Tiles[letter].Count - l.used[letter]
+}
+
+func (l *Ledger) IsWildcard(cell Cell) bool {
+	return l.wildcards[l.index(cell)]
+}
+
+// Wildcards are the cells using a wildcard, in reading order
+func (l *Ledger) Wildcards() []Cell {
+	cells := []Cell{}
+	for idx, isWildcard := range l.wildcards {
+		if isWildcard {
+			cells = append(cells, l.cell(idx))
+		}
+	}
+	return cells
+}
+
+// Tile is the tile consumed by a cell. Regular tiles of a letter are numbered in reading order.
+func (l *Ledger) Tile(cell Cell) (TileRef, bool) {
+	idx := l.index(cell)
+	letter := l.letters[idx]
+	if letter == 0 {
+		return TileRef{}, false
+	}
+	if l.wildcards[idx] {
+		return TileRef{Letter: letter, Instance: -1, Wildcard: true}, true
+	}
+	instance := 0
+	for other := 0; other < idx; other++ {
+		if l.letters[other] == letter && !l.wildcards[other] {
+			instance++
+		}
+	}
+	return TileRef{Letter: letter, Instance: instance}, true
+}
+
+// ChooseWildcards moves each inferred wildcard to whichever cell of its letter gives the highest
+// score and returns that score. score is given the wildcard cells of a candidate assignment.
+// Wildcards placed with PlaceWildcard stay where they are.
+func (l *Ledger) ChooseWildcards(score func(wildcards []Cell) int) int {
+	// Each letter short of tiles keeps the same number of wildcards, but they can sit on any of its cells
+	type letterChoice struct {
+		cells []int
+		count int
+	}
+	choices := []letterChoice{}
+	seen := map[rune]bool{}
+	fixed := []int{}
+	for idx, letter := range l.letters {
+		if l.fixed[idx] {
+			fixed = append(fixed, idx)
+		}
+		if !l.wildcards[idx] || l.fixed[idx] || seen[letter] {
+			continue
+		}
+		seen[letter] = true
+		choice := letterChoice{}
+		for other, otherLetter := range l.letters {
+			if otherLetter == letter && !l.fixed[other] {
+				choice.cells = append(choice.cells, other)
+				if l.wildcards[other] {
+					choice.count++
+				}
+			}
+		}
+		choices = append(choices, choice)
+	}
+
+	var best []int
+	bestScore := 0
+	found := false
+	var choose func(choiceIdx int, picked []int)
+	choose = func(choiceIdx int, picked []int) {
+		if choiceIdx == len(choices) {
+			cells := make([]Cell, len(picked))
+			for i, idx := range picked {
+				cells[i] = l.cell(idx)
+			}
+			// Ties go to the first assignment in reading order
+			if candidate := score(cells); !found || candidate > bestScore {
+				found = true
+				best = slices.Clone(picked)
+				bestScore = candidate
+			}
+			return
+		}
+		choice := choices[choiceIdx]
+		for _, combination := range combinations(choice.cells, choice.count) {
+			choose(choiceIdx+1, append(slices.Clone(picked), combination...))
+		}
+	}
+	choose(0, fixed)
+
+	for idx := range l.wildcards {
+		l.wildcards[idx] = false
+	}
+	for _, idx := range best {
+		l.wildcards[idx] = true
+	}
+	return bestScore
+}
+
+func (l *Ledger) index(cell Cell) int {
+	return cell.Row*l.board.Size.Cols + cell.Col
+}
+
+func (l *Ledger) cell(idx int) Cell {
+	return Cell{Row: idx / l.board.Size.Cols, Col: idx % l.board.Size.Cols}
+}
+
+// combinations of k items, in order
+func combinations(items []int, k int) [][]int {
+	if k == 0 {
+		return [][]int{{}}
+	}
+	if len(items) < k {
+		return nil
+	}
+	with := combinations(items[1:], k-1)
+	for i := range with {
+		with[i] = append([]int{items[0]}, with[i]...)
+	}
+	return append(with, combinations(items[1:], k)...)
+}
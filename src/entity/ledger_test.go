@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ledgerBoard = &Board{
+	Size: Size{Rows: 2, Cols: 3},
+	Tiles: map[rune]Tile{
+		'A': {Value: 1, Count: 2},
+		'B': {Value: 5, Count: 1},
+	},
+}
+
+func TestLedger_PlaceAndUndo(t *testing.T) {
+	ledger := NewLedger(ledgerBoard, 1)
+
+	tile, ok := ledger.Place(Cell{0, 0}, 'A')
+	assert.True(t, ok)
+	assert.Equal(t, TileRef{Letter: 'A', Instance: 0}, tile)
+	tile, ok = ledger.Place(Cell{1, 0}, 'A')
+	assert.True(t, ok)
+	assert.Equal(t, TileRef{Letter: 'A', Instance: 1}, tile)
+	assert.Equal(t, 0, ledger.Remaining('A'))
+
+	tile, ok = ledger.Place(Cell{0, 1}, 'A')
+	assert.True(t, ok)
+	assert.Equal(t, TileRef{Letter: 'A', Instance: -1, Wildcard: true}, tile)
+
+	_, ok = ledger.Place(Cell{0, 2}, 'B')
+	assert.True(t, ok)
+	_, ok = ledger.Place(Cell{1, 1}, 'B')
+	assert.False(t, ok, "out of tiles and wildcards")
+	_, ok = ledger.Place(Cell{0, 0}, 'B')
+	assert.False(t, ok, "cell already filled")
+
+	// (0,0) and (0,1) read before (1,0), so instance numbers follow reading order
+	tile, _ = ledger.Tile(Cell{1, 0})
+	assert.Equal(t, TileRef{Letter: 'A', Instance: 1}, tile)
+
+	cell, letter, ok := ledger.Undo()
+	assert.True(t, ok)
+	assert.Equal(t, Cell{0, 2}, cell)
+	assert.Equal(t, 'B', letter)
+	assert.Equal(t, 1, ledger.Remaining('B'))
+
+	// Move the wildcard A to (0,0), so the most recent placement at (0,1) is a regular A
+	ledger.ChooseWildcards(func(wildcards []Cell) int {
+		if wildcards[0] == (Cell{0, 0}) {
+			return 10
+		}
+		return 5
+	})
+	assert.Equal(t, []Cell{{0, 0}}, ledger.Wildcards())
+
+	// Undoing the regular A frees a tile for the wildcard A
+	cell, letter, ok = ledger.Undo()
+	assert.True(t, ok)
+	assert.Equal(t, Cell{0, 1}, cell)
+	assert.Equal(t, 'A', letter)
+	assert.Empty(t, ledger.Wildcards())
+	assert.Equal(t, 0, ledger.Remaining('A'))
+	tile, _ = ledger.Tile(Cell{0, 0})
+	assert.Equal(t, TileRef{Letter: 'A', Instance: 0}, tile)
+
+	ledger.Undo()
+	assert.Equal(t, 1, ledger.Remaining('A'))
+	ledger.Undo()
+	assert.Equal(t, 2, ledger.Remaining('A'))
+	_, _, ok = ledger.Undo()
+	assert.False(t, ok)
+}
+
+func TestLedger_ChooseWildcards(t *testing.T) {
+	ledger := NewLedger(ledgerBoard, 1)
+	ledger.Place(Cell{0, 0}, 'B')
+	ledger.Place(Cell{1, 2}, 'B')
+	assert.Equal(t, []Cell{{1, 2}}, ledger.Wildcards())
+
+	// Pretend (1,2) is worth more than (0,0)
+	score := ledger.ChooseWildcards(func(wildcards []Cell) int {
+		if wildcards[0] == (Cell{0, 0}) {
+			return 10
+		}
+		return 5
+	})
+	assert.Equal(t, 10, score)
+	assert.Equal(t, []Cell{{0, 0}}, ledger.Wildcards())
+	tile, _ := ledger.Tile(Cell{1, 2})
+	assert.Equal(t, TileRef{Letter: 'B', Instance: 0}, tile)
+}
+
+func TestLedger_PlaceWildcard(t *testing.T) {
+	ledger := NewLedger(ledgerBoard, 1)
+	tile, ok := ledger.PlaceWildcard(Cell{0, 0}, 'B')
+	assert.True(t, ok)
+	assert.True(t, tile.Wildcard)
+	assert.Equal(t, 1, ledger.Remaining('B'), "tile is still available")
+	ledger.Place(Cell{1, 2}, 'B')
+
+	_, ok = ledger.PlaceWildcard(Cell{0, 1}, 'A')
+	assert.False(t, ok, "out of wildcards")
+
+	// Explicit wildcards stay put even when another cell scores better
+	ledger.ChooseWildcards(func(wildcards []Cell) int {
+		if wildcards[0] == (Cell{1, 2}) {
+			return 10
+		}
+		return 5
+	})
+	assert.Equal(t, []Cell{{0, 0}}, ledger.Wildcards())
+}
@@ -0,0 +1,38 @@
+package entity
+
+// Metadata is the puzzle statistics Puzzmo publishes with a board. Every word in it was accepted.
+type Metadata struct {
+	Best  MetadataSolution
+	Worst MetadataSolution
+	// ScoreBands are the "Highest Scoring Words" lists, highest band first
+	ScoreBands []ScoreBand
+}
+
+type MetadataSolution struct {
+	Words []string
+	// BonusWord is empty if the solution did not complete it
+	BonusWord string
+	Score     int
+}
+
+// ScoreBand lists words scoring between Low and High
+type ScoreBand struct {
+	Low   int
+	High  int
+	Words []string
+}
+
+// Words lists every word mentioned, in order of appearance. Words may repeat.
+func (m *Metadata) Words() []string {
+	words := []string{}
+	for _, solution := range []MetadataSolution{m.Best, m.Worst} {
+		words = append(words, solution.Words...)
+		if solution.BonusWord != "" {
+			words = append(words, solution.BonusWord)
+		}
+	}
+	for _, band := range m.ScoreBands {
+		words = append(words, band.Words...)
+	}
+	return words
+}
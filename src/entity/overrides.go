@@ -0,0 +1,63 @@
+package entity
+
+import "slices"
+
+// OverrideAction is a change made to the base word list
+type OverrideAction string
+
+const (
+	OverrideAdd      OverrideAction = "add"
+	OverrideRemove   OverrideAction = "remove"
+	OverrideCommon   OverrideAction = "common"
+	OverrideUncommon OverrideAction = "uncommon"
+)
+
+var OverrideActions = []OverrideAction{OverrideAdd, OverrideRemove, OverrideCommon, OverrideUncommon}
+
+type Override struct {
+	Action OverrideAction
+	Word   string
+}
+
+// Overrides are applied in order on top of the base word list, so later entries win
+type Overrides []Override
+
+// Set records an action for a word, replacing any earlier one it contradicts
+func (o Overrides) Set(word string, action OverrideAction) Overrides {
+	o = slices.DeleteFunc(o, func(override Override) bool {
+		return override.Word == word && override.Action.opposes(action)
+	})
+	return append(o, Override{Action: action, Word: word})
+}
+
+// Reset drops every override for a word
+func (o Overrides) Reset(word string) Overrides {
+	return slices.DeleteFunc(o, func(override Override) bool {
+		return override.Word == word
+	})
+}
+
+// Membership is the last add or remove for a word, or "" if there is none
+func (o Overrides) Membership(word string) OverrideAction {
+	return o.last(word, OverrideAdd, OverrideRemove)
+}
+
+// Commonness is the last common or uncommon mark for a word, or "" if there is none
+func (o Overrides) Commonness(word string) OverrideAction {
+	return o.last(word, OverrideCommon, OverrideUncommon)
+}
+
+func (o Overrides) last(word string, actions ...OverrideAction) OverrideAction {
+	for i := len(o) - 1; i >= 0; i-- {
+		if o[i].Word == word && slices.Contains(actions, o[i].Action) {
+			return o[i].Action
+		}
+	}
+	return ""
+}
+
+// opposes is whether two actions set the same thing, so only one of them can hold
+func (a OverrideAction) opposes(other OverrideAction) bool {
+	membership := []OverrideAction{OverrideAdd, OverrideRemove}
+	return slices.Contains(membership, a) == slices.Contains(membership, other)
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "fmt"
+
+// Rules are the scoring and placement rules of the game. The defaults match Puzzmo's Bongo.
+type Rules struct {
+	// MaxWildcards is how many letters may be played without a matching tile
+	MaxWildcards int
+	// CommonMultiplier is applied to the score of common words
+	CommonMultiplier float64
+	// MinWordLength and MaxWordLength bound which dictionary words can be played
+	MinWordLength int
+	MaxWordLength int
+	// OneWordPerRow only scores a row when all of its letters form a single word.
+	// Otherwise each run of letters between blanks is scored as its own word. The scorer
+	// supports both, but the solver only places one word per row.
+	OneWordPerRow bool
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		MaxWildcards:     1,
+		CommonMultiplier: 1.3,
+		MinWordLength:    3,
+		MaxWordLength:    5,
+		OneWordPerRow:    true,
+	}
+}
+
+func (r Rules) IsPlayableLength(length int) bool {
+	return length >= r.MinWordLength && length <= r.MaxWordLength
+}
+
+// Validate reports rules that can't be played by
+func (r Rules) Validate() error {
+	switch {
+	case r.MaxWildcards < 0:
+		return fmt.Errorf("invalid rules: max wildcards %d is negative", r.MaxWildcards)
+	case r.CommonMultiplier <= 0:
+		return fmt.Errorf("invalid rules: common multiplier %g must be positive", r.CommonMultiplier)
+	case r.MinWordLength < 1:
+		return fmt.Errorf("invalid rules: min word length %d must be at least 1", r.MinWordLength)
+	case r.MinWordLength > r.MaxWordLength:
+		return fmt.Errorf("invalid rules: min word length %d is longer than max word length %d", r.MinWordLength, r.MaxWordLength)
+	}
+	return nil
+}
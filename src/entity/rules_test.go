@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules_Validate(t *testing.T) {
+	assert.NoError(t, DefaultRules().Validate())
+
+	for name, modify := range map[string]func(*Rules){
+		"negative wildcards": func(r *Rules) { r.MaxWildcards = -1 },
+		"zero multiplier":    func(r *Rules) { r.CommonMultiplier = 0 },
+		"zero min length":    func(r *Rules) { r.MinWordLength = 0 },
+		"min over max":       func(r *Rules) { r.MinWordLength, r.MaxWordLength = 6, 5 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			rules := DefaultRules()
+			modify(&rules)
+			assert.Error(t, rules.Validate())
+		})
+	}
+}
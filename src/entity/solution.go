@@ -1,17 +1,63 @@
 package entity
 
-type Solution []rune
+import "unicode"
+
+type Solution struct {
+	Size    Size
+	Letters []rune
+	// Wildcards marks cells played with the wildcard tile rather than a letter tile
+	Wildcards []bool
+}
+
+// WildcardMarker goes before a letter played with the wildcard tile when a solution is written
+// out, like SW*ORN. Letters themselves are read in either case.
+const WildcardMarker = '*'
+
+// NewSolution lays out letters row by row, with WildcardMarker before wildcards. Missing letters
+// are left blank.
+func NewSolution(size Size, letters string) Solution {
+	solution := EmptySolution(size)
+	runes, wildcards := ParseDisplayRow(letters)
+	for i, letter := range runes {
+		if i >= size.Cells() {
+			break
+		}
+		solution.Set(i/size.Cols, i%size.Cols, letter)
+		solution.SetWildcard(i/size.Cols, i%size.Cols, wildcards[i])
+	}
+	return solution
+}
+
+// ParseDisplayRow reads a row written like DisplayRows, returning its uppercase letters and which
+// of them are wildcards
+func ParseDisplayRow(row string) ([]rune, []bool) {
+	letters := []rune{}
+	wildcards := []bool{}
+	wildcard := false
+	for _, letter := range row {
+		if letter == WildcardMarker {
+			wildcard = true
+			continue
+		}
+		letters = append(letters, unicode.ToUpper(letter))
+		wildcards = append(wildcards, wildcard)
+		wildcard = false
+	}
+	return letters, wildcards
+}
 
 func (s Solution) Get(i, j int) rune {
-	return s[i*BoardSize+j]
+	return s.Letters[i*s.Size.Cols+j]
 }
 
 func (s Solution) GetRow(i int) []rune {
-	return s[i*BoardSize : (i+1)*BoardSize]
+	return s.Letters[i*s.Size.Cols : (i+1)*s.Size.Cols]
 }
 
+// Set places a letter played with a regular tile, in either case
 func (s Solution) Set(i, j int, letter rune) {
-	s[i*BoardSize+j] = letter
+	s.Letters[i*s.Size.Cols+j] = unicode.ToUpper(letter)
+	s.Wildcards[i*s.Size.Cols+j] = false
 }
 
 func (s Solution) SetRow(i int, word []rune) {
@@ -20,26 +66,77 @@ func (s Solution) SetRow(i int, word []rune) {
 	}
 }
 
+func (s Solution) IsWildcard(i, j int) bool {
+	return s.Wildcards[i*s.Size.Cols+j]
+}
+
+func (s Solution) SetWildcard(i, j int, isWildcard bool) {
+	s.Wildcards[i*s.Size.Cols+j] = isWildcard
+}
+
+// WildcardCells are the cells marked as wildcards, in reading order
+func (s Solution) WildcardCells() []Cell {
+	cells := []Cell{}
+	for idx, isWildcard := range s.Wildcards {
+		if isWildcard {
+			cells = append(cells, Cell{Row: idx / s.Size.Cols, Col: idx % s.Size.Cols})
+		}
+	}
+	return cells
+}
+
 func (s Solution) Rows() [][]rune {
-	rows := make([][]rune, BoardSize)
-	for i := 0; i < BoardSize; i++ {
-		rows[i] = s[i*BoardSize : (i+1)*BoardSize]
+	rows := make([][]rune, s.Size.Rows)
+	for i := 0; i < s.Size.Rows; i++ {
+		rows[i] = s.GetRow(i)
+	}
+	return rows
+}
+
+func (s Solution) Clone() Solution {
+	letters := make([]rune, len(s.Letters))
+	copy(letters, s.Letters)
+	wildcards := make([]bool, len(s.Wildcards))
+	copy(wildcards, s.Wildcards)
+	return Solution{
+		Size:      s.Size,
+		Letters:   letters,
+		Wildcards: wildcards,
+	}
+}
+
+// DisplayRows are the rows with WildcardMarker before wildcards
+func (s Solution) DisplayRows() []string {
+	rows := make([]string, s.Size.Rows)
+	for i, row := range s.Rows() {
+		display := make([]rune, 0, len(row))
+		for j, letter := range row {
+			if s.IsWildcard(i, j) {
+				display = append(display, WildcardMarker)
+			}
+			display = append(display, letter)
+		}
+		rows[i] = string(display)
 	}
 	return rows
 }
 
 func (s Solution) String() string {
 	ret := ""
-	for _, row := range s.Rows() {
-		ret += string(row) + "|"
+	for _, row := range s.DisplayRows() {
+		ret += row + "|"
 	}
 	return ret
 }
 
-func EmptySolution() Solution {
-	empty := make([]rune, BoardSize*BoardSize)
-	for i := 0; i < BoardSize*BoardSize; i++ {
+func EmptySolution(size Size) Solution {
+	empty := make([]rune, size.Cells())
+	for i := 0; i < size.Cells(); i++ {
 		empty[i] = ' '
 	}
-	return empty
+	return Solution{
+		Size:      size,
+		Letters:   empty,
+		Wildcards: make([]bool, size.Cells()),
+	}
 }
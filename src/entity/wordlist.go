@@ -1,15 +1,121 @@
 package entity
 
+import (
+	"slices"
+	"sync"
+)
+
+// WordList is a minimized directed acyclic word graph. Words that share a suffix share the
+// nodes for it, so unlike a trie a node does not know which prefix led to it.
 type WordList struct {
 	Root *DAGNode
-	// map of column index -> rune -> nodes
-	NodeMap map[int]map[rune][]*DAGNode
+
+	// prefixes caches Prefixes lookups by column and letter, since they walk the whole graph
+	mu       sync.Mutex
+	prefixes map[int]map[rune][]Prefix
 }
 
 type DAGNode struct {
-	// Fragment is the letters so far up to this node
-	Fragment []rune
-	Children map[rune]*DAGNode
+	// Edges to child nodes, sorted by letter
+	Edges []DAGEdge
 	// IsWord marks if current node makes a valid word (still can have children)
 	IsWord bool
+	// IsCommon marks if the word ending here earns the common word multiplier
+	IsCommon bool
+}
+
+type DAGEdge struct {
+	Letter rune
+	Node   *DAGNode
+}
+
+// Prefix is a path from the root along with the node it ends at
+type Prefix struct {
+	Letters []rune
+	Node    *DAGNode
+}
+
+func NewWordList(root *DAGNode) *WordList {
+	return &WordList{
+		Root:     root,
+		prefixes: map[int]map[rune][]Prefix{},
+	}
+}
+
+// Child follows the edge for a letter, or returns nil if there is none
+func (n *DAGNode) Child(letter rune) *DAGNode {
+	idx, ok := slices.BinarySearchFunc(n.Edges, letter, func(edge DAGEdge, letter rune) int {
+		return int(edge.Letter - letter)
+	})
+	if !ok {
+		return nil
+	}
+	return n.Edges[idx].Node
+}
+
+// Contains is whether a word is in the list
+func (w *WordList) Contains(word string) bool {
+	node := w.Root
+	for _, letter := range word {
+		if node = node.Child(letter); node == nil {
+			return false
+		}
+	}
+	return node.IsWord
+}
+
+// IsCommon is whether a word is in the list and common
+func (w *WordList) IsCommon(word string) bool {
+	node := w.Root
+	for _, letter := range word {
+		if node = node.Child(letter); node == nil {
+			return false
+		}
+	}
+	return node.IsWord && node.IsCommon
+}
+
+// Words lists every word in alphabetical order
+func (w *WordList) Words() []string {
+	words := []string{}
+	var walk func(node *DAGNode, letters []rune)
+	walk = func(node *DAGNode, letters []rune) {
+		if node.IsWord {
+			words = append(words, string(letters))
+		}
+		for _, edge := range node.Edges {
+			walk(edge.Node, append(letters, edge.Letter))
+		}
+	}
+	walk(w.Root, []rune{})
+	return words
+}
+
+// Prefixes lists every prefix of a word that has letter at col (0-indexed), in alphabetical order.
+// This is what a row with a tile already placed at col can start with.
+func (w *WordList) Prefixes(col int, letter rune) []Prefix {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.prefixes[col] == nil {
+		w.prefixes[col] = w.collectPrefixes(col)
+	}
+	return w.prefixes[col][letter]
+}
+
+// collectPrefixes finds every prefix of length col+1, grouped by last letter
+func (w *WordList) collectPrefixes(col int) map[rune][]Prefix {
+	byLetter := map[rune][]Prefix{}
+	var walk func(node *DAGNode, letters []rune)
+	walk = func(node *DAGNode, letters []rune) {
+		if len(letters) == col+1 {
+			letter := letters[col]
+			byLetter[letter] = append(byLetter[letter], Prefix{Letters: slices.Clone(letters), Node: node})
+			return
+		}
+		for _, edge := range node.Edges {
+			walk(edge.Node, append(letters, edge.Letter))
+		}
+	}
+	walk(w.Root, make([]rune, 0, col+1))
+	return byLetter
 }
@@ -0,0 +1,184 @@
+package gameimporter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/azhu2/bongo/src/config/dates"
+)
+
+// ArchiveSource names the archive as the source of boards it already had
+const ArchiveSource = "archive"
+
+// ArchiveModule puts the archive in front of whichever Gateway is provided. Decorators only
+// apply within their own module, so this must be given to the app directly rather than nested.
+var ArchiveModule = fx.Options(
+	fx.Decorate(DecorateArchive),
+)
+
+// Archiver is implemented by Gateways that keep the boards they import
+type Archiver interface {
+	// ArchivedPath is where a date's board is kept, and whether it has been saved yet
+	ArchivedPath(date string) (string, bool)
+}
+
+type ArchiveConfig struct {
+	// Dir holds one board per date. Defaults to bongo/boards under the user cache directory.
+	Dir string
+	// Disabled imports every board from the wrapped Gateway without saving it
+	Disabled bool
+}
+
+// ResolveDir is Dir, or its default under the user cache directory
+func (c ArchiveConfig) ResolveDir() (string, error) {
+	if c.Dir != "" {
+		return c.Dir, nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("no user cache directory %w", err)
+	}
+	return filepath.Join(cacheDir, "bongo", "boards"), nil
+}
+
+type ArchiveParams struct {
+	fx.In
+
+	Gateway   Gateway
+	Config    ArchiveConfig `optional:"true"`
+	Selection Selection     `optional:"true"`
+	// Dates files boards imported for today under today's date. Optional.
+	Dates dates.Resolver `optional:"true"`
+}
+
+// archiveGateway serves boards it has seen before from disk, and saves new ones as they are
+// imported, in whichever format they came in
+type archiveGateway struct {
+	dir     string
+	dates   dates.Resolver
+	wrapped Gateway
+}
+
+// NewArchive stores every board wrapped imports under dir, by date
+func NewArchive(wrapped Gateway, dir string) Gateway {
+	return &archiveGateway{
+		dir:     dir,
+		wrapped: wrapped,
+	}
+}
+
+func DecorateArchive(p ArchiveParams) (Gateway, error) {
+	if p.Config.Disabled {
+		return p.Gateway, nil
+	}
+	// Boards are archived by date alone, which only holds the first puzzle of each day
+	if !p.Selection.IsDefault() {
+		slog.Debug("board archive skipped for puzzle selection", "selection", p.Selection)
+		return p.Gateway, nil
+	}
+	dir, err := p.Config.ResolveDir()
+	if err != nil {
+		// Still works, just fetches every time
+		slog.Warn("board archive disabled", "err", err)
+		return p.Gateway, nil
+	}
+	return &archiveGateway{
+		dir:     dir,
+		dates:   p.Dates,
+		wrapped: p.Gateway,
+	}, nil
+}
+
+func (a *archiveGateway) ImportBoard(ctx context.Context, date string) (string, error) {
+	imported, err := a.ImportSourcedBoard(ctx, date)
+	if err != nil {
+		return "", err
+	}
+	return imported.Board, nil
+}
+
+func (a *archiveGateway) ImportSourcedBoard(ctx context.Context, date string) (*Import, error) {
+	if date == "" && a.dates != nil {
+		date = a.dates.Today()
+	}
+	// Without a date there's nothing to file the board under
+	if date == "" {
+		return ImportSourcedBoard(ctx, a.wrapped, date)
+	}
+	// Dates become file names, so don't let them reach outside the archive
+	if _, err := time.Parse(dates.Format, date); err != nil {
+		return nil, fmt.Errorf("invalid board date %s %w", date, err)
+	}
+
+	if path, ok := a.ArchivedPath(date); ok {
+		raw, err := os.ReadFile(path)
+		if err == nil {
+			slog.Debug("loaded game board",
+				"source", ArchiveSource,
+				"path", path,
+			)
+			return &Import{Board: string(raw), Source: ArchiveSource, Extension: filepath.Ext(path)}, nil
+		}
+		slog.Warn("unable to read archived board", "date", date, "err", err)
+	}
+
+	imported, err := ImportSourcedBoard(ctx, a.wrapped, date)
+	if err != nil {
+		return nil, err
+	}
+	// A board that can't be archived is still usable
+	if err := a.save(date, imported); err != nil {
+		slog.Warn("unable to archive board", "date", date, "err", err)
+	}
+	return imported, nil
+}
+
+// save keeps the board under the extension of the format it came in, so it reads back the same way
+func (a *archiveGateway) save(date string, imported *Import) error {
+	if err := os.MkdirAll(a.dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create board archive %w", err)
+	}
+	extension := imported.Extension
+	if !slices.Contains(BoardExtensions, extension) {
+		return fmt.Errorf("unknown board format %q", extension)
+	}
+	path := filepath.Join(a.dir, date+extension)
+	// Write to a temp file first so a concurrent run never reads half a board
+	tmp, err := os.CreateTemp(a.dir, date+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create archived board %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(imported.Board); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write archived board %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write archived board %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("unable to write archived board %w", err)
+	}
+	slog.Debug("archived game board",
+		"path", path,
+	)
+	return nil
+}
+
+// ArchivedPath finds the date's board in any format. Boards not saved yet would go in the text format.
+func (a *archiveGateway) ArchivedPath(date string) (string, bool) {
+	for _, extension := range BoardExtensions {
+		path := filepath.Join(a.dir, date+extension)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return filepath.Join(a.dir, date+textExtension), false
+}
@@ -0,0 +1,140 @@
+package gameimporter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingGateway answers with a fixed board and counts how often it was asked
+type countingGateway struct {
+	board string
+	err   error
+	calls int
+}
+
+func (c *countingGateway) ImportBoard(_ context.Context, _ string) (string, error) {
+	c.calls++
+	return c.board, c.err
+}
+
+func TestArchive(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "boards")
+	wrapped := &countingGateway{board: "2\n5x5\n"}
+	archive := NewArchive(wrapped, dir)
+
+	board, err := archive.ImportBoard(ctx, "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, "2\n5x5\n", board)
+	saved, err := os.ReadFile(filepath.Join(dir, "2024-12-23.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, board, string(saved))
+
+	// Served from disk the second time
+	board, err = archive.ImportBoard(ctx, "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, "2\n5x5\n", board)
+	assert.Equal(t, 1, wrapped.calls)
+
+	// No date to file it under
+	_, err = archive.ImportBoard(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, wrapped.calls)
+
+	_, err = archive.ImportBoard(ctx, "../2024-12-23")
+	assert.Error(t, err)
+	assert.Equal(t, 2, wrapped.calls)
+}
+
+// Boards are kept in the format they came in, so they parse the same way when read back
+func TestArchive_Format(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	archive := NewArchive(Chain(Source{Name: "file", Gateway: &fileImporter{dir: testdataDir}}), dir)
+
+	_, err := archive.ImportBoard(ctx, "2024-12-23")
+	require.NoError(t, err)
+	path, ok := archive.(Archiver).ArchivedPath("2024-12-23")
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, "2024-12-23.txt"), path)
+
+	// A JSON board isn't filed as text
+	archive = NewArchive(&jsonGateway{}, dir)
+	_, err = archive.ImportBoard(ctx, "2024-12-24")
+	require.NoError(t, err)
+	path, ok = archive.(Archiver).ArchivedPath("2024-12-24")
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, "2024-12-24.json"), path)
+	imported, err := ImportSourcedBoard(ctx, archive, "2024-12-24")
+	require.NoError(t, err)
+	assert.Equal(t, &Import{Board: "{}", Source: ArchiveSource, Extension: ".json"}, imported)
+
+	_, ok = archive.(Archiver).ArchivedPath("2024-12-25")
+	assert.False(t, ok)
+}
+
+type jsonGateway struct{}
+
+func (jsonGateway) ImportBoard(_ context.Context, _ string) (string, error) {
+	return "{}", nil
+}
+
+func (jsonGateway) ImportSourcedBoard(_ context.Context, _ string) (*Import, error) {
+	return &Import{Board: "{}", Source: "json", Extension: ".json"}, nil
+}
+
+type fixedToday string
+
+func (d fixedToday) Today() string {
+	return string(d)
+}
+
+func (d fixedToday) Resolve(value string) (string, error) {
+	return string(d), nil
+}
+
+// Today's board is filed under today's date
+func TestArchive_Today(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	wrapped := &countingGateway{board: "board"}
+	archive, err := DecorateArchive(ArchiveParams{Gateway: wrapped, Config: ArchiveConfig{Dir: dir}, Dates: fixedToday("2024-12-24")})
+	require.NoError(t, err)
+
+	_, err = archive.ImportBoard(ctx, "")
+	require.NoError(t, err)
+	_, err = archive.ImportBoard(ctx, "2024-12-24")
+	require.NoError(t, err)
+	assert.Equal(t, 1, wrapped.calls)
+	assert.FileExists(t, filepath.Join(dir, "2024-12-24.txt"))
+}
+
+func TestArchive_Error(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	wrapped := &countingGateway{err: errors.New("offline")}
+	archive := NewArchive(wrapped, dir)
+
+	_, err := archive.ImportBoard(ctx, "2024-12-23")
+	assert.ErrorIs(t, err, wrapped.err)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "failed imports should not be archived")
+}
+
+func TestDecorateArchive(t *testing.T) {
+	wrapped := &countingGateway{}
+	gateway, err := DecorateArchive(ArchiveParams{Gateway: wrapped, Config: ArchiveConfig{Disabled: true}})
+	require.NoError(t, err)
+	assert.Same(t, wrapped, gateway)
+
+	gateway, err = DecorateArchive(ArchiveParams{Gateway: wrapped, Config: ArchiveConfig{Dir: t.TempDir()}})
+	require.NoError(t, err)
+	assert.IsType(t, &archiveGateway{}, gateway)
+}
@@ -0,0 +1,108 @@
+package gameimporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Import is a board along with where it came from
+type Import struct {
+	Board string
+	// Source names the Gateway that had the board, or is empty if unknown
+	Source string
+	// Extension is the file extension of the board's format
+	Extension string
+}
+
+// SourcedGateway is implemented by Gateways that can tell where an imported board came from
+type SourcedGateway interface {
+	Gateway
+	// ImportSourcedBoard is ImportBoard, also reporting where the board came from
+	ImportSourcedBoard(ctx context.Context, date string) (*Import, error)
+}
+
+// ImportSourcedBoard imports a board from g, reporting its source if g can tell.
+// Boards from Gateways that can't are assumed to be in the Puzzmo text format.
+func ImportSourcedBoard(ctx context.Context, g Gateway, date string) (*Import, error) {
+	if sourced, ok := g.(SourcedGateway); ok {
+		return sourced.ImportSourcedBoard(ctx, date)
+	}
+	board, err := g.ImportBoard(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	return &Import{Board: board, Extension: textExtension}, nil
+}
+
+// Source is a named Gateway to try as part of a chain
+type Source struct {
+	Name string
+	Gateway
+}
+
+type chainGateway struct {
+	sources []Source
+}
+
+// NewChain imports boards from the configured boards directory if they're there, and from Puzzmo
+// otherwise. The archive goes in front of this as a decorator, so it also saves whatever the chain
+// finds.
+func NewChain(p Params) (Result, error) {
+	graphqlResult, err := NewGraphql(p)
+	if err != nil {
+		return Result{}, err
+	}
+	// Board files only hold the first puzzle of each day
+	if p.File.Dir == "" || !p.Selection.IsDefault() {
+		return Result{
+			Gateway: Chain(Source{Name: "graphql", Gateway: graphqlResult.Gateway}),
+		}, nil
+	}
+	fileResult, err := NewFile(p)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Gateway: Chain(
+			Source{Name: "file", Gateway: fileResult.Gateway},
+			Source{Name: "graphql", Gateway: graphqlResult.Gateway},
+		),
+	}, nil
+}
+
+// Chain tries each source in order until one has the board
+func Chain(sources ...Source) Gateway {
+	return &chainGateway{sources: sources}
+}
+
+func (c *chainGateway) ImportBoard(ctx context.Context, date string) (string, error) {
+	imported, err := c.ImportSourcedBoard(ctx, date)
+	if err != nil {
+		return "", err
+	}
+	return imported.Board, nil
+}
+
+func (c *chainGateway) ImportSourcedBoard(ctx context.Context, date string) (*Import, error) {
+	errs := []error{}
+	for _, source := range c.sources {
+		imported, err := ImportSourcedBoard(ctx, source.Gateway, date)
+		if err != nil {
+			slog.Debug("board source failed, trying next",
+				"source", source.Name,
+				"err", err,
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name, err))
+			continue
+		}
+		slog.Info("imported game board",
+			"source", source.Name,
+			"date", date,
+		)
+		imported.Source = source.Name
+		return imported, nil
+	}
+	return nil, fmt.Errorf("unable to import board from any source %w", errors.Join(errs...))
+}
@@ -0,0 +1,92 @@
+package gameimporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	missing := &countingGateway{err: errors.New("not archived")}
+	found := &countingGateway{board: "board"}
+	unused := &countingGateway{board: "other board"}
+	chain := Chain(
+		Source{Name: "missing", Gateway: missing},
+		Source{Name: "found", Gateway: found},
+		Source{Name: "unused", Gateway: unused},
+	)
+
+	board, err := chain.ImportBoard(ctx, "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, "board", board)
+	assert.Equal(t, 1, missing.calls)
+	assert.Equal(t, 1, found.calls)
+	assert.Equal(t, 0, unused.calls)
+
+	imported, err := ImportSourcedBoard(ctx, chain, "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, &Import{Board: "board", Source: "found", Extension: ".txt"}, imported)
+
+	// The archive names itself once it has the board, and the chain's source until then
+	archive := NewArchive(Chain(Source{Name: "found", Gateway: found}), t.TempDir())
+	imported, err = ImportSourcedBoard(ctx, archive, "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, "found", imported.Source)
+	imported, err = ImportSourcedBoard(ctx, archive, "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, ArchiveSource, imported.Source)
+
+	// Gateways that can't tell have no source
+	imported, err = ImportSourcedBoard(ctx, found, "2024-12-23")
+	require.NoError(t, err)
+	assert.Empty(t, imported.Source)
+}
+
+func TestChain_AllFail(t *testing.T) {
+	ctx := context.Background()
+	fileErr := errors.New("not archived")
+	graphqlErr := errors.New("offline")
+	chain := Chain(
+		Source{Name: "file", Gateway: &countingGateway{err: fileErr}},
+		Source{Name: "graphql", Gateway: &countingGateway{err: graphqlErr}},
+	)
+
+	_, err := chain.ImportBoard(ctx, "2024-12-23")
+	assert.ErrorIs(t, err, fileErr)
+	assert.ErrorIs(t, err, graphqlErr)
+	assert.ErrorContains(t, err, "file: not archived")
+	assert.ErrorContains(t, err, "graphql: offline")
+}
+
+// Boards in the boards directory never need Puzzmo
+func TestNewChain_File(t *testing.T) {
+	ctx := context.Background()
+	server, params := newPuzzmo(t)
+	chainResult, err := NewChain(params)
+	require.NoError(t, err)
+	fileResult, err := NewFile(params)
+	require.NoError(t, err)
+
+	imported, err := ImportSourcedBoard(ctx, chainResult.Gateway, "2024-12-23")
+	require.NoError(t, err)
+	fileBoard, err := fileResult.Gateway.ImportBoard(ctx, "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, fileBoard, imported.Board)
+	assert.Equal(t, "file", imported.Source)
+	assert.Empty(t, server.Requests())
+
+	// Without one, everything comes from Puzzmo
+	params.File = FileConfig{}
+	_, err = NewFile(params)
+	assert.Error(t, err)
+	chainResult, err = NewChain(params)
+	require.NoError(t, err)
+	imported, err = ImportSourcedBoard(ctx, chainResult.Gateway, "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, fileBoard, imported.Board)
+	assert.Equal(t, "graphql", imported.Source)
+}
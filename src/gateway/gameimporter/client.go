@@ -0,0 +1,208 @@
+package gameimporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/machinebox/graphql"
+	"go.uber.org/fx"
+
+	"github.com/azhu2/bongo/src/util/sleep"
+)
+
+// ErrUnauthorized means Puzzmo turned down the credentials, which retrying won't fix
+var ErrUnauthorized = errors.New("puzzmo rejected the credentials, refresh AUTH_TOKEN in .env")
+
+// GraphqlConfig tunes requests to Puzzmo. Zero values fall back to the defaults.
+type GraphqlConfig struct {
+	// Endpoint is Puzzmo's GraphQL API, or a stand-in for it
+	Endpoint string
+	// Attempts is how many times a request is tried when it fails with a transient error
+	Attempts int
+	// Backoff is the wait before the first retry. It doubles for each retry after, up to
+	// MaxBackoff, and is jittered so parallel runs don't retry in lockstep.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Timeout bounds each attempt
+	Timeout time.Duration
+	// Interval is the least time between requests to the same host
+	Interval time.Duration
+}
+
+func DefaultGraphqlConfig() GraphqlConfig {
+	return GraphqlConfig{
+		Endpoint:   GraphqlEndpoint,
+		Attempts:   4,
+		Backoff:    500 * time.Millisecond,
+		MaxBackoff: 10 * time.Second,
+		Timeout:    15 * time.Second,
+		Interval:   250 * time.Millisecond,
+	}
+}
+
+func (c GraphqlConfig) withDefaults() GraphqlConfig {
+	defaults := DefaultGraphqlConfig()
+	if c.Endpoint == "" {
+		c.Endpoint = defaults.Endpoint
+	}
+	if c.Attempts <= 0 {
+		c.Attempts = defaults.Attempts
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = defaults.Backoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = defaults.MaxBackoff
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaults.Timeout
+	}
+	if c.Interval < 0 {
+		c.Interval = 0
+	}
+	return c
+}
+
+type ClientParams struct {
+	fx.In
+
+	Config GraphqlConfig `optional:"true"`
+}
+
+// NewGraphqlClient is a Puzzmo client that spaces out requests to each host and reports HTTP
+// errors by status, so they can be told apart from GraphQL errors
+func NewGraphqlClient(p ClientParams) (*graphql.Client, error) {
+	config := p.Config.withDefaults()
+	return graphql.NewClient(config.Endpoint, graphql.WithHTTPClient(newHTTPClient(config))), nil
+}
+
+func newHTTPClient(config GraphqlConfig) *http.Client {
+	return &http.Client{
+		Transport: &statusTransport{
+			wrapped: &rateLimitTransport{
+				interval: config.Interval,
+				next:     map[string]time.Time{},
+				wrapped:  http.DefaultTransport,
+			},
+		},
+	}
+}
+
+// StatusError is an HTTP response that wasn't a success
+type StatusError struct {
+	Code int
+	// Body is the start of the response, which usually says what went wrong
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("puzzmo responded %d %s: %s", e.Code, http.StatusText(e.Code), e.Body)
+}
+
+// statusTransport turns error responses into a StatusError. The graphql client would otherwise
+// try to decode them and fail without saying why.
+type statusTransport struct {
+	wrapped http.RoundTripper
+}
+
+func (t *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.wrapped.RoundTrip(req)
+	if err != nil || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+	return nil, &StatusError{Code: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+}
+
+// rateLimitTransport holds each request until interval has passed since the last one to its host
+type rateLimitTransport struct {
+	interval time.Duration
+	wrapped  http.RoundTripper
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	slot := time.Now()
+	if next := t.next[req.URL.Host]; next.After(slot) {
+		slot = next
+	}
+	t.next[req.URL.Host] = slot.Add(t.interval)
+	t.mu.Unlock()
+
+	if err := sleep.Until(req.Context(), slot); err != nil {
+		return nil, err
+	}
+	return t.wrapped.RoundTrip(req)
+}
+
+// run makes a request, retrying transient failures with backoff
+func (g *graphqlGateway) run(ctx context.Context, req *graphql.Request, resp any) error {
+	backoff := g.config.Backoff
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, g.config.Timeout)
+		err := g.graphqlClient.Run(attemptCtx, req, resp)
+		cancel()
+		switch {
+		case err == nil:
+			return nil
+		case isUnauthorized(err):
+			return fmt.Errorf("%w (%w)", ErrUnauthorized, err)
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case !isTransient(err) || attempt >= g.config.Attempts:
+			return err
+		}
+
+		// Equal jitter: wait somewhere between half and all of the backoff
+		wait := backoff/2 + rand.N(backoff/2+1)
+		slog.Debug("retrying puzzmo request",
+			"attempt", attempt,
+			"wait", wait,
+			"err", err,
+		)
+		if err := sleep.For(ctx, wait); err != nil {
+			return err
+		}
+		backoff = min(backoff*2, g.config.MaxBackoff)
+	}
+}
+
+func isUnauthorized(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code == http.StatusUnauthorized || statusErr.Code == http.StatusForbidden
+	}
+	// Puzzmo can also answer 200 with a GraphQL error about the token
+	message := strings.ToLower(err.Error())
+	for _, hint := range []string{"unauthorized", "unauthenticated", "not authenticated", "not logged in"} {
+		if strings.Contains(message, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransient is whether an error might go away if the request is tried again
+func isTransient(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code == http.StatusTooManyRequests || statusErr.Code >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
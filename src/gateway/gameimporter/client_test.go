@@ -0,0 +1,115 @@
+package gameimporter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azhu2/bongo/src/gateway/gameimporter/puzzmotest"
+)
+
+// newTestGateway imports from a stand-in Puzzmo with a board for 2024-12-23
+func newTestGateway(t *testing.T, config GraphqlConfig) (Gateway, *puzzmotest.Server) {
+	server := puzzmotest.NewServer(t)
+	server.AddBoard("2024-12-23", "board")
+	config.Endpoint = server.URL
+	client, err := NewGraphqlClient(ClientParams{Config: config})
+	require.NoError(t, err)
+	result, err := NewGraphql(Params{GraphqlClient: client, GraphqlConfig: config})
+	require.NoError(t, err)
+	return result.Gateway, server
+}
+
+func TestGraphql_Retry(t *testing.T) {
+	ctx := context.Background()
+	gateway, server := newTestGateway(t, GraphqlConfig{Backoff: time.Millisecond})
+	server.Fail(puzzmotest.Fault{Status: http.StatusServiceUnavailable}, puzzmotest.Fault{Status: http.StatusBadGateway})
+
+	board, err := gateway.ImportBoard(ctx, "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, "board", board)
+	assert.Len(t, server.Requests(), 3)
+}
+
+func TestGraphql_RetryExhausted(t *testing.T) {
+	ctx := context.Background()
+	gateway, server := newTestGateway(t, GraphqlConfig{Attempts: 2, Backoff: time.Millisecond})
+	server.Fail(puzzmotest.Fault{Status: http.StatusTooManyRequests}, puzzmotest.Fault{Status: http.StatusTooManyRequests})
+
+	_, err := gateway.ImportBoard(ctx, "2024-12-23")
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.Code)
+	assert.Len(t, server.Requests(), 2)
+}
+
+func TestGraphql_NotRetried(t *testing.T) {
+	tests := []struct {
+		name         string
+		fault        puzzmotest.Fault
+		unauthorized bool
+	}{
+		{
+			name:         "unauthorized status",
+			fault:        puzzmotest.Fault{Status: http.StatusUnauthorized},
+			unauthorized: true,
+		},
+		{
+			name:         "unauthorized graphql error",
+			fault:        puzzmotest.Fault{Message: "Unauthorized"},
+			unauthorized: true,
+		},
+		{
+			name:  "bad request",
+			fault: puzzmotest.Fault{Status: http.StatusBadRequest, Message: "bad query"},
+		},
+		{
+			name:  "graphql error",
+			fault: puzzmotest.Fault{Message: "Cannot query field"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			gateway, server := newTestGateway(t, GraphqlConfig{Backoff: time.Millisecond})
+			server.Fail(tt.fault)
+
+			_, err := gateway.ImportBoard(ctx, "2024-12-23")
+			require.Error(t, err)
+			if tt.unauthorized {
+				assert.ErrorIs(t, err, ErrUnauthorized)
+			} else {
+				assert.NotErrorIs(t, err, ErrUnauthorized)
+			}
+			assert.Len(t, server.Requests(), 1)
+		})
+	}
+}
+
+func TestGraphql_Timeout(t *testing.T) {
+	ctx := context.Background()
+	gateway, server := newTestGateway(t, GraphqlConfig{Attempts: 2, Backoff: time.Millisecond, Timeout: 10 * time.Millisecond})
+	server.Fail(puzzmotest.Fault{Delay: 50 * time.Millisecond})
+
+	board, err := gateway.ImportBoard(ctx, "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, "board", board)
+	assert.Len(t, server.Requests(), 2)
+}
+
+func TestGraphql_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	gateway, _ := newTestGateway(t, GraphqlConfig{Interval: 30 * time.Millisecond})
+
+	start := time.Now()
+	for range 3 {
+		_, err := gateway.ImportBoard(ctx, "2024-12-23")
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+}
@@ -2,41 +2,64 @@ package gameimporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
-	"runtime"
-
-	"go.uber.org/fx"
 )
 
-const (
-	fileFormat = "../../../../testdata/%s.txt"
-)
+// textExtension is for boards in the Puzzmo text format
+const textExtension = ".txt"
 
-var FileModule = fx.Module("importer",
-	fx.Provide(NewFile),
-)
+// BoardExtensions are the file extensions boards may be kept under, one for each format the
+// parser reads
+var BoardExtensions = []string{textExtension, ".json", ".yaml", ".yml"}
+
+type FileConfig struct {
+	// Dir holds boards named by date, like the repo's testdata. Boards are only read from files
+	// when it's set.
+	Dir string
+}
 
-type fileImporter struct{}
+// fileImporter reads boards from a directory of files named by date
+type fileImporter struct {
+	dir string
+}
 
 func NewFile(p Params) (Result, error) {
+	if p.File.Dir == "" {
+		return Result{}, errors.New("no boards directory to import files from")
+	}
 	return Result{
-		Gateway: &fileImporter{},
+		Gateway: &fileImporter{dir: p.File.Dir},
 	}, nil
 }
 
 func (f *fileImporter) ImportBoard(ctx context.Context, date string) (string, error) {
-	_, file, _, _ := runtime.Caller(0)
-	path := filepath.Join(file, fmt.Sprintf(fileFormat, date))
-	raw, err := os.ReadFile(path)
+	imported, err := f.ImportSourcedBoard(ctx, date)
 	if err != nil {
 		return "", err
 	}
-	slog.Debug("loaded game board",
-		"source", "file",
-		"path", path,
-	)
-	return string(raw), nil
+	return imported.Board, nil
+}
+
+func (f *fileImporter) ImportSourcedBoard(_ context.Context, date string) (*Import, error) {
+	for _, extension := range BoardExtensions {
+		path := filepath.Join(f.dir, date+extension)
+		raw, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("loaded game board",
+			"source", "file",
+			"path", path,
+		)
+		return &Import{Board: string(raw), Source: "file", Extension: extension}, nil
+	}
+	return nil, fmt.Errorf("no board file found for %s: %w", date, fs.ErrNotExist)
 }
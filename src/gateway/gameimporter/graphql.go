@@ -7,42 +7,58 @@ import (
 	"strings"
 
 	"github.com/machinebox/graphql"
-	"go.uber.org/fx"
 )
 
 const (
+	// GraphqlEndpoint is the default GraphqlConfig.Endpoint
 	GraphqlEndpoint = "https://www.puzzmo.com/_api/prod/graphql"
-	gameKey         = "today:/%s/bongo"
-)
-
-var GraphqlModule = fx.Module("graphqlimporter",
-	fx.Provide(NewGraphql),
+	// dateKey is replaced with the date being imported in finder keys
+	dateKey = "{date}"
 )
 
 type graphqlGateway struct {
 	userID    string
 	authToken string
+	config    GraphqlConfig
+	selection Selection
 
 	graphqlClient *graphql.Client
 }
 
 func NewGraphql(p Params) (Result, error) {
 	return Result{
-		Gateway: &graphqlGateway{
-			userID:    p.Secrets.UserID,
-			authToken: p.Secrets.AuthToken,
-
-			graphqlClient: p.GraphqlClient,
-		},
+		Gateway: newGraphqlGateway(p),
 	}, nil
 }
 
+// NewPuzzleLister lists puzzles straight from Puzzmo, since only it knows about variants
+func NewPuzzleLister(p Params) (PuzzleLister, error) {
+	return newGraphqlGateway(p), nil
+}
+
+func newGraphqlGateway(p Params) *graphqlGateway {
+	return &graphqlGateway{
+		userID:    p.Secrets.UserID,
+		authToken: p.Secrets.AuthToken,
+		config:    p.GraphqlConfig.withDefaults(),
+		selection: p.Selection,
+
+		graphqlClient: p.GraphqlClient,
+	}
+}
+
 func (g *graphqlGateway) ImportBoard(ctx context.Context, date string) (string, error) {
+	if g.selection.FinderKey != "" {
+		if strings.Contains(g.selection.FinderKey, dateKey) && date == "" {
+			return "", fmt.Errorf("finder key %s needs a date", g.selection.FinderKey)
+		}
+		return g.importBoardFromGameScreen(ctx, strings.ReplaceAll(g.selection.FinderKey, dateKey, date))
+	}
 	return g.importBoardFromDailyScreen(ctx, date)
 }
 
-// (legacy) importing directly from game screen. Assumes game slug
-func (g *graphqlGateway) importBoardFromGameScreen(ctx context.Context, date string) (string, error) {
+// Import board from the game screen, which can find puzzles the daily screen doesn't list
+func (g *graphqlGateway) importBoardFromGameScreen(ctx context.Context, finderKey string) (string, error) {
 	req := graphql.NewRequest(`
 		query PlayGameScreenQuery(
 			$finderKey: String!
@@ -65,7 +81,7 @@ func (g *graphqlGateway) importBoardFromGameScreen(ctx context.Context, date str
 			}
 		}
 	`)
-	req.Var("finderKey", fmt.Sprintf(gameKey, date))
+	req.Var("finderKey", finderKey)
 	req.Var("gameContext", map[string]any{
 		"partnerSlug":             nil,
 		"pingOwnerForMultiplayer": true,
@@ -76,22 +92,49 @@ func (g *graphqlGateway) importBoardFromGameScreen(ctx context.Context, date str
 	req.Header.Set("puzzmo-gameplay-id", g.userID)
 
 	var resp graphqlBoardResponse
-	err := g.graphqlClient.Run(ctx, req, &resp)
-	board := resp.StartOrFindGameplay.GamePlayed.Puzzle.Puzzle
-	if err != nil || len(board) == 0 {
+	if err := g.run(ctx, req, &resp); err != nil {
 		return "", fmt.Errorf("unable to fetch Bongo board from Puzzmo %w", err)
 	}
 
+	gameplay := resp.StartOrFindGameplay
+	if gameplay.Failed {
+		err := fmt.Errorf("puzzmo could not start %s: %s", finderKey, gameplay.Message)
+		if isUnauthorized(err) {
+			return "", fmt.Errorf("%w (%w)", ErrUnauthorized, err)
+		}
+		return "", err
+	}
+	if gameplay.GamePlayed == nil || len(gameplay.GamePlayed.Puzzle.Puzzle) == 0 {
+		return "", fmt.Errorf("no bongo board for %s in %s response", finderKey, gameplay.Typename)
+	}
+
 	slog.Debug("loaded game board",
 		"source", "graphql",
-		"date", date,
+		"finder_key", finderKey,
 	)
 
-	return board, err
+	return gameplay.GamePlayed.Puzzle.Puzzle, nil
 }
 
-// Import board from daily game screen. Grabs the first game with "bongo" in its slug
+// Import board from daily game screen, picking among the games with "bongo" in their slug
 func (g *graphqlGateway) importBoardFromDailyScreen(ctx context.Context, date string) (string, error) {
+	puzzles, err := g.ListPuzzles(ctx, date)
+	if err != nil {
+		return "", err
+	}
+	puzzle, err := g.selection.Select(puzzles)
+	if err != nil {
+		return "", err
+	}
+	slog.Debug("loaded game board",
+		"source", "graphql",
+		"slug", puzzle.Slug,
+		"url_path", puzzle.URLPath,
+	)
+	return puzzle.Board, nil
+}
+
+func (g *graphqlGateway) ListPuzzles(ctx context.Context, date string) ([]Puzzle, error) {
 	req := graphql.NewRequest(`
 		query TodayScreenQuery(
 			$day: String
@@ -118,27 +161,31 @@ func (g *graphqlGateway) importBoardFromDailyScreen(ctx context.Context, date st
 	req.Header.Set("puzzmo-gameplay-id", g.userID)
 
 	var resp graphqlTodayScreenResponse
-	err := g.graphqlClient.Run(ctx, req, &resp)
+	err := g.run(ctx, req, &resp)
 
 	if err != nil {
-		return "", fmt.Errorf("unable to fetch daily puzzles from Puzzmo %w", err)
+		return nil, fmt.Errorf("unable to fetch daily puzzles from Puzzmo %w", err)
 	}
 
+	puzzles := []Puzzle{}
 	for _, puzzle := range resp.TodayPage.Daily.Puzzles {
-		if strings.Contains(puzzle.Puzzle.Game.Slug, "bongo") {
-			board := puzzle.Puzzle.Puzzle
-			if len(board) > 0 {
-				slog.Debug("loaded game board",
-					"source", "graphql",
-					"slug", puzzle.Puzzle.Game.Slug,
-				)
-				return board, nil
-			}
+		if !strings.Contains(puzzle.Puzzle.Game.Slug, "bongo") {
+			continue
+		}
+		if len(puzzle.Puzzle.Puzzle) == 0 {
 			slog.Warn("found empty board",
 				"slug", puzzle.Puzzle.Game.Slug,
 			)
+			continue
 		}
+		puzzles = append(puzzles, Puzzle{
+			Slug:    puzzle.Puzzle.Game.Slug,
+			URLPath: puzzle.UrlPath,
+			Board:   puzzle.Puzzle.Puzzle,
+		})
 	}
-
-	return "", fmt.Errorf("no bongo boards found")
+	if len(puzzles) == 0 {
+		return nil, fmt.Errorf("no bongo boards found")
+	}
+	return puzzles, nil
 }
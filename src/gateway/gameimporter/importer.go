@@ -9,6 +9,11 @@ import (
 	"github.com/azhu2/bongo/src/config/secrets"
 )
 
+// Module imports boards through a chain of sources, see NewChain
+var Module = fx.Module("gameimporter",
+	fx.Provide(NewChain, NewGraphqlClient, NewPuzzleLister),
+)
+
 type Gateway interface {
 	ImportBoard(ctx context.Context, date string) (string, error)
 }
@@ -18,6 +23,11 @@ type Params struct {
 
 	secrets.Secrets
 	GraphqlClient *graphql.Client
+	GraphqlConfig GraphqlConfig `optional:"true"`
+	// Selection picks among a day's bongo puzzles. Optional.
+	Selection Selection `optional:"true"`
+	// File is where local boards are read from. Optional.
+	File FileConfig `optional:"true"`
 }
 
 type Result struct {
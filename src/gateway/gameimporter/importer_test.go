@@ -4,26 +4,39 @@ import (
 	"context"
 	"testing"
 
-	"github.com/joho/godotenv"
-	"github.com/machinebox/graphql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/azhu2/bongo/src/config/secrets"
+	"github.com/azhu2/bongo/src/gateway/gameimporter/puzzmotest"
 	"github.com/azhu2/bongo/testdata"
 )
 
+var testSecrets = secrets.Secrets{UserID: "user", AuthToken: "token"}
+
+const testdataDir = "../../../testdata"
+
+// newPuzzmo serves the testdata boards, with each day as today in turn
+func newPuzzmo(t *testing.T) (*puzzmotest.Server, Params) {
+	server := puzzmotest.NewServer(t)
+	require.NoError(t, server.LoadDir(testdataDir))
+	server.RequireToken(testSecrets.AuthToken)
+	config := GraphqlConfig{Endpoint: server.URL}
+	client, err := NewGraphqlClient(ClientParams{Config: config})
+	require.NoError(t, err)
+	return server, Params{
+		Secrets:       testSecrets,
+		GraphqlClient: client,
+		GraphqlConfig: config,
+		File:          FileConfig{Dir: testdataDir},
+	}
+}
+
 // Compare results of graphql and file importer
 func TestImportBoard(t *testing.T) {
 	for _, tt := range testdata.TestData {
 		t.Run(tt.Date, func(t *testing.T) {
-			godotenv.Load("../../../.env")
-			secretResult, err := secrets.New()
-			require.NoError(t, err)
-			params := Params{
-				Secrets:       secretResult.Secrets,
-				GraphqlClient: graphql.NewClient(GraphqlEndpoint),
-			}
+			server, params := newPuzzmo(t)
 			graphqlResult, err := NewGraphql(params)
 			require.NoError(t, err)
 			fileResult, err := NewFile(params)
@@ -35,6 +48,41 @@ func TestImportBoard(t *testing.T) {
 			assert.NoError(t, err)
 
 			assert.Equal(t, graphqlImport, fileImport)
+
+			requests := server.Requests()
+			require.Len(t, requests, 1)
+			assert.Equal(t, "TodayScreenQuery", requests[0].Operation)
+			assert.Equal(t, "user", requests[0].Header.Get("puzzmo-gameplay-id"))
 		})
 	}
 }
+
+func TestImportBoard_Daily(t *testing.T) {
+	ctx := context.Background()
+	server, params := newPuzzmo(t)
+	server.SetToday("2025-01-01")
+	server.AddPuzzle("2025-01-01", puzzmotest.Puzzle{Slug: "crossword", Board: "crossword board"})
+	server.AddPuzzle("2025-01-01", puzzmotest.Puzzle{Slug: "bongo", Board: ""})
+	server.AddPuzzle("2025-01-01", puzzmotest.Puzzle{Slug: "bongo-weekly", Board: "bongo board"})
+	graphqlResult, err := NewGraphql(params)
+	require.NoError(t, err)
+
+	// Skips the other game and the empty board
+	board, err := graphqlResult.Gateway.ImportBoard(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "bongo board", board)
+
+	_, err = graphqlResult.Gateway.ImportBoard(ctx, "2025-01-02")
+	assert.Error(t, err, "no puzzles that day")
+}
+
+func TestImportBoard_WrongToken(t *testing.T) {
+	ctx := context.Background()
+	_, params := newPuzzmo(t)
+	params.Secrets.AuthToken = "expired"
+	graphqlResult, err := NewGraphql(params)
+	require.NoError(t, err)
+
+	_, err = graphqlResult.Gateway.ImportBoard(ctx, "2024-12-23")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
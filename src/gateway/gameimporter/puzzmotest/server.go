@@ -0,0 +1,243 @@
+// Package puzzmotest is a stand-in for Puzzmo's GraphQL API, so importers can be tested offline
+package puzzmotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var operationRegex = regexp.MustCompile(`(?:query|mutation)\s+(\w+)`)
+
+// Server answers TodayScreenQuery and PlayGameScreenQuery from the puzzles it's been given
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	today    string
+	token    string
+	days     map[string][]Puzzle
+	faults   []Fault
+	requests []Request
+}
+
+// Puzzle is one game on a day's page
+type Puzzle struct {
+	Slug    string
+	URLPath string
+	Board   string
+}
+
+// Fault replaces the next response. A Fault with only a Delay still answers normally, late.
+type Fault struct {
+	// Status is an HTTP status to respond with, with Message as the body
+	Status int
+	// Message is a GraphQL error to respond with, if there's no Status
+	Message string
+	// Failed answers PlayGameScreenQuery with a failed ErrorableResponse, with Message as its message
+	Failed bool
+	Delay  time.Duration
+}
+
+// Request is what the server was asked
+type Request struct {
+	Operation string
+	Variables map[string]any
+	Header    http.Header
+}
+
+// NewServer starts a server that is closed when the test ends
+func NewServer(t testing.TB) *Server {
+	s := &Server{days: map[string][]Puzzle{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// SetToday is the day TodayScreenQuery answers for when it isn't given one
+func (s *Server) SetToday(date string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.today = date
+}
+
+// RequireToken rejects requests whose authorization header isn't token with a 401
+func (s *Server) RequireToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// AddPuzzle adds a game to a day's page, after any already there
+func (s *Server) AddPuzzle(date string, puzzle Puzzle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.days[date] = append(s.days[date], puzzle)
+}
+
+// AddBoard adds the day's bongo puzzle
+func (s *Server) AddBoard(date, board string) {
+	s.AddPuzzle(date, Puzzle{
+		Slug:    "bongo",
+		URLPath: fmt.Sprintf("/play/%s/bongo", date),
+		Board:   board,
+	})
+}
+
+// LoadDir adds a bongo puzzle for each archived board in dir, named like 2024-12-23.txt
+func (s *Server) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		s.AddBoard(strings.TrimSuffix(filepath.Base(path), ".txt"), string(raw))
+	}
+	return nil
+}
+
+// Fail queues faults for the next requests, one each
+func (s *Server) Fail(faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, faults...)
+}
+
+// Requests lists everything asked so far, oldest first
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	operation := ""
+	if match := operationRegex.FindStringSubmatch(body.Query); match != nil {
+		operation = match[1]
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Operation: operation, Variables: body.Variables, Header: r.Header.Clone()})
+	fault := Fault{}
+	if len(s.faults) > 0 {
+		fault, s.faults = s.faults[0], s.faults[1:]
+	}
+	token := s.token
+	s.mu.Unlock()
+
+	if fault.Delay > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(fault.Delay):
+		}
+	}
+	switch {
+	case fault.Status != 0:
+		http.Error(w, fault.Message, fault.Status)
+		return
+	case fault.Failed:
+		writeJSON(w, map[string]any{"data": failedGameplay(fault.Message)})
+		return
+	case fault.Message != "":
+		writeJSON(w, map[string]any{"errors": []map[string]any{{"message": fault.Message}}})
+		return
+	case token != "" && r.Header.Get("authorization") != token:
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	switch operation {
+	case "TodayScreenQuery":
+		day, _ := body.Variables["day"].(string)
+		writeJSON(w, map[string]any{"data": s.todayPage(day)})
+	case "PlayGameScreenQuery":
+		finderKey, _ := body.Variables["finderKey"].(string)
+		writeJSON(w, map[string]any{"data": s.gameplay(finderKey)})
+	default:
+		writeJSON(w, map[string]any{"errors": []map[string]any{{"message": fmt.Sprintf("unknown operation %q", operation)}}})
+	}
+}
+
+func (s *Server) todayPage(day string) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if day == "" {
+		day = s.today
+	}
+	puzzles := []map[string]any{}
+	for _, puzzle := range s.days[day] {
+		puzzles = append(puzzles, map[string]any{
+			"urlPath": puzzle.URLPath,
+			"puzzle": map[string]any{
+				"puzzle": puzzle.Board,
+				"game":   map[string]any{"slug": puzzle.Slug},
+			},
+		})
+	}
+	return map[string]any{
+		"todayPage": map[string]any{
+			"daily": map[string]any{"puzzles": puzzles},
+		},
+	}
+}
+
+// gameplay finds the puzzle for a finder key like today:/2024-12-23/bongo
+func (s *Server) gameplay(finderKey string) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parts := strings.Split(strings.TrimPrefix(finderKey, "today:/"), "/")
+	if len(parts) == 2 {
+		date, slug := parts[0], parts[1]
+		for _, puzzle := range s.days[date] {
+			if puzzle.Slug == slug {
+				return map[string]any{
+					"startOrFindGameplay": map[string]any{
+						"__typename": "GamePlayed",
+						"gamePlayed": map[string]any{
+							"puzzle": map[string]any{"puzzle": puzzle.Board},
+						},
+					},
+				}
+			}
+		}
+	}
+	return failedGameplay(fmt.Sprintf("no puzzle found for %s", finderKey))
+}
+
+func failedGameplay(message string) map[string]any {
+	return map[string]any{
+		"startOrFindGameplay": map[string]any{
+			"__typename": "ErrorResponse",
+			"message":    message,
+			"failed":     true,
+			"success":    false,
+		},
+	}
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,84 @@
+package gameimporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Puzzle is one of a day's bongo puzzles
+type Puzzle struct {
+	Slug    string
+	URLPath string
+	Board   string
+}
+
+// PuzzleLister is implemented by Gateways that can tell apart the puzzles on a day
+type PuzzleLister interface {
+	// ListPuzzles returns the day's bongo puzzles in the order Puzzmo shows them
+	ListPuzzles(ctx context.Context, date string) ([]Puzzle, error)
+}
+
+// Selection picks one of a day's bongo puzzles. The zero value picks the first.
+type Selection struct {
+	Slug    string
+	URLPath string
+	// Index counts from 0, in the order ListPuzzles returns
+	Index int
+	// FinderKey skips the daily screen and asks for a puzzle directly, like today:/{date}/bongo.
+	// {date} is replaced with the date being imported.
+	FinderKey string
+}
+
+// ParseSelection reads a number as an index, anything with a : as a finder key, anything
+// starting with / as a URL path, and anything else as a slug
+func ParseSelection(value string) Selection {
+	if index, err := strconv.Atoi(value); err == nil {
+		return Selection{Index: index}
+	}
+	if strings.Contains(value, ":") {
+		return Selection{FinderKey: value}
+	}
+	if strings.HasPrefix(value, "/") {
+		return Selection{URLPath: value}
+	}
+	return Selection{Slug: value}
+}
+
+// IsDefault is whether the selection is the first puzzle, which is what archives and board files hold
+func (s Selection) IsDefault() bool {
+	return s == Selection{}
+}
+
+func (s Selection) String() string {
+	switch {
+	case s.FinderKey != "":
+		return s.FinderKey
+	case s.Slug != "":
+		return s.Slug
+	case s.URLPath != "":
+		return s.URLPath
+	default:
+		return strconv.Itoa(s.Index)
+	}
+}
+
+// Select finds the selected puzzle
+func (s Selection) Select(puzzles []Puzzle) (Puzzle, error) {
+	for i, puzzle := range puzzles {
+		switch {
+		case s.Slug != "":
+			if puzzle.Slug == s.Slug {
+				return puzzle, nil
+			}
+		case s.URLPath != "":
+			if puzzle.URLPath == s.URLPath {
+				return puzzle, nil
+			}
+		case i == s.Index:
+			return puzzle, nil
+		}
+	}
+	return Puzzle{}, fmt.Errorf("no bongo puzzle %s among %d", s, len(puzzles))
+}
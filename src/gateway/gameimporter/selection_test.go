@@ -0,0 +1,117 @@
+package gameimporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azhu2/bongo/src/gateway/gameimporter/puzzmotest"
+)
+
+func TestParseSelection(t *testing.T) {
+	assert.Equal(t, Selection{Index: 2}, ParseSelection("2"))
+	assert.Equal(t, Selection{URLPath: "/play/bongo/weekly"}, ParseSelection("/play/bongo/weekly"))
+	assert.Equal(t, Selection{Slug: "bongo-weekly"}, ParseSelection("bongo-weekly"))
+	assert.Equal(t, Selection{FinderKey: "today:/{date}/bongo"}, ParseSelection("today:/{date}/bongo"))
+	assert.True(t, ParseSelection("0").IsDefault())
+}
+
+func TestListPuzzles(t *testing.T) {
+	ctx := context.Background()
+	server, params := newPuzzmo(t)
+	server.AddPuzzle("2025-01-01", puzzmotest.Puzzle{Slug: "bongo", URLPath: "/play/bongo", Board: "daily"})
+	server.AddPuzzle("2025-01-01", puzzmotest.Puzzle{Slug: "crossword", URLPath: "/play/crossword", Board: "crossword"})
+	server.AddPuzzle("2025-01-01", puzzmotest.Puzzle{Slug: "bongo-partner", URLPath: "/partner/news/bongo", Board: "partner"})
+
+	lister, err := NewPuzzleLister(params)
+	require.NoError(t, err)
+	puzzles, err := lister.ListPuzzles(ctx, "2025-01-01")
+	require.NoError(t, err)
+	assert.Equal(t, []Puzzle{
+		{Slug: "bongo", URLPath: "/play/bongo", Board: "daily"},
+		{Slug: "bongo-partner", URLPath: "/partner/news/bongo", Board: "partner"},
+	}, puzzles)
+
+	tests := []struct {
+		selection Selection
+		expected  string
+	}{
+		{Selection{}, "daily"},
+		{Selection{Index: 1}, "partner"},
+		{Selection{Slug: "bongo-partner"}, "partner"},
+		{Selection{URLPath: "/play/bongo"}, "daily"},
+	}
+	for _, tt := range tests {
+		params.Selection = tt.selection
+		graphqlResult, err := NewGraphql(params)
+		require.NoError(t, err)
+		board, err := graphqlResult.Gateway.ImportBoard(ctx, "2025-01-01")
+		require.NoError(t, err, tt.selection.String())
+		assert.Equal(t, tt.expected, board, tt.selection.String())
+	}
+
+	params.Selection = Selection{Slug: "crossword"}
+	graphqlResult, err := NewGraphql(params)
+	require.NoError(t, err)
+	_, err = graphqlResult.Gateway.ImportBoard(ctx, "2025-01-01")
+	assert.Error(t, err, "not a bongo puzzle")
+}
+
+// Archives and testdata only hold the first puzzle, so other selections go straight to Puzzmo
+func TestSelection_SkipsArchive(t *testing.T) {
+	ctx := context.Background()
+	server, params := newPuzzmo(t)
+	server.AddPuzzle("2024-12-23", puzzmotest.Puzzle{Slug: "bongo-partner", Board: "partner"})
+	params.Selection = Selection{Slug: "bongo-partner"}
+
+	chainResult, err := NewChain(params)
+	require.NoError(t, err)
+	gateway, err := DecorateArchive(ArchiveParams{Gateway: chainResult.Gateway, Config: ArchiveConfig{Dir: t.TempDir()}, Selection: params.Selection})
+	require.NoError(t, err)
+	board, err := gateway.ImportBoard(ctx, "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, "partner", board)
+	_, ok := gateway.(Archiver)
+	assert.False(t, ok)
+}
+
+func TestImportBoard_GameScreen(t *testing.T) {
+	ctx := context.Background()
+	server, params := newPuzzmo(t)
+	server.AddPuzzle("2025-01-01", puzzmotest.Puzzle{Slug: "bongo-partner", Board: "partner"})
+	server.AddPuzzle("2025-01-01", puzzmotest.Puzzle{Slug: "bongo-empty", Board: ""})
+
+	importBoard := func(finderKey, date string) (string, error) {
+		params.Selection = ParseSelection(finderKey)
+		graphqlResult, err := NewGraphql(params)
+		require.NoError(t, err)
+		return graphqlResult.Gateway.ImportBoard(ctx, date)
+	}
+
+	board, err := importBoard("today:/{date}/bongo-partner", "2025-01-01")
+	require.NoError(t, err)
+	assert.Equal(t, "partner", board)
+	requests := server.Requests()
+	assert.Equal(t, "PlayGameScreenQuery", requests[len(requests)-1].Operation)
+	assert.Equal(t, "today:/2025-01-01/bongo-partner", requests[len(requests)-1].Variables["finderKey"])
+
+	// Fixed keys ignore the date
+	board, err = importBoard("today:/2025-01-01/bongo-partner", "2024-12-23")
+	require.NoError(t, err)
+	assert.Equal(t, "partner", board)
+
+	_, err = importBoard("today:/{date}/bongo-partner", "")
+	assert.ErrorContains(t, err, "needs a date")
+
+	_, err = importBoard("today:/{date}/bongo-missing", "2025-01-01")
+	assert.ErrorContains(t, err, "no puzzle found for today:/2025-01-01/bongo-missing")
+
+	_, err = importBoard("today:/{date}/bongo-empty", "2025-01-01")
+	assert.ErrorContains(t, err, "no bongo board")
+
+	server.Fail(puzzmotest.Fault{Failed: true, Message: "Not logged in"})
+	_, err = importBoard("today:/{date}/bongo-partner", "2025-01-01")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
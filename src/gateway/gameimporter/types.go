@@ -2,7 +2,13 @@ package gameimporter
 
 type graphqlBoardResponse struct {
 	StartOrFindGameplay struct {
-		GamePlayed struct {
+		Typename string `json:"__typename"`
+		// ErrorableResponse
+		Message string
+		Failed  bool
+		Success bool
+		// HasGamePlayed
+		GamePlayed *struct {
 			Puzzle struct {
 				Puzzle string
 			}
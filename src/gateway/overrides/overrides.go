@@ -0,0 +1,135 @@
+package overrides
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.uber.org/fx"
+
+	"github.com/azhu2/bongo/src/entity"
+	"github.com/azhu2/bongo/src/gateway/wordlistimporter"
+)
+
+const fileHeader = "# Word list overrides, applied in order. One \"<action> <WORD>\" per line,\n" +
+	"# where action is add, remove, common or uncommon.\n"
+
+var Module = fx.Module("overrides",
+	fx.Provide(New),
+)
+
+// Gateway stores the user's word list overrides
+type Gateway interface {
+	// Load returns no overrides if none have been saved yet
+	Load(ctx context.Context) (entity.Overrides, error)
+	Save(ctx context.Context, overrides entity.Overrides) error
+	// Path is where overrides are stored
+	Path() string
+}
+
+type Config struct {
+	// Path defaults to bongo/overrides.txt under the user config directory
+	Path string
+}
+
+type Params struct {
+	fx.In
+
+	Config Config `optional:"true"`
+}
+
+type Result struct {
+	fx.Out
+
+	Gateway
+}
+
+type gateway struct {
+	path string
+}
+
+func New(p Params) (Result, error) {
+	path := p.Config.Path
+	if path == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			// Still usable without overrides, they just can't be saved
+			slog.Warn("no user config directory, word list overrides disabled", "err", err)
+		} else {
+			path = filepath.Join(configDir, "bongo", "overrides.txt")
+		}
+	}
+	return Result{
+		Gateway: &gateway{path: path},
+	}, nil
+}
+
+func (g *gateway) Path() string {
+	return g.path
+}
+
+func (g *gateway) Load(_ context.Context) (entity.Overrides, error) {
+	if g.path == "" {
+		return entity.Overrides{}, nil
+	}
+	file, err := os.Open(g.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entity.Overrides{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open overrides %w", err)
+	}
+	defer file.Close()
+
+	overrides := entity.Overrides{}
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		action := entity.OverrideAction(strings.ToLower(fields[0]))
+		if len(fields) != 2 || !slices.Contains(entity.OverrideActions, action) {
+			return nil, fmt.Errorf("unable to parse override on line %d of %s: %s", lineNumber, g.path, line)
+		}
+		word, err := wordlistimporter.Normalize(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse override on line %d of %s: %w", lineNumber, g.path, err)
+		}
+		overrides = append(overrides, entity.Override{Action: action, Word: word})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read overrides %w", err)
+	}
+	slog.Debug("loaded word list overrides",
+		"path", g.path,
+		"count", len(overrides),
+	)
+	return overrides, nil
+}
+
+func (g *gateway) Save(_ context.Context, overrides entity.Overrides) error {
+	if g.path == "" {
+		return fmt.Errorf("no path to save overrides to")
+	}
+	if err := os.MkdirAll(filepath.Dir(g.path), 0o755); err != nil {
+		return fmt.Errorf("unable to create overrides directory %w", err)
+	}
+	var contents strings.Builder
+	contents.WriteString(fileHeader)
+	for _, override := range overrides {
+		fmt.Fprintf(&contents, "%s %s\n", override.Action, override.Word)
+	}
+	if err := os.WriteFile(g.path, []byte(contents.String()), 0o644); err != nil {
+		return fmt.Errorf("unable to write overrides %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package overrides
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azhu2/bongo/src/entity"
+)
+
+func TestOverrides_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nested", "overrides.txt")
+	result, err := New(Params{Config: Config{Path: path}})
+	require.NoError(t, err)
+
+	loaded, err := result.Gateway.Load(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, loaded, "nothing saved yet")
+
+	saved := entity.Overrides{
+		{Action: entity.OverrideAdd, Word: "QOPH"},
+		{Action: entity.OverrideUncommon, Word: "QOPH"},
+		{Action: entity.OverrideRemove, Word: "LSD"},
+	}
+	require.NoError(t, result.Gateway.Save(ctx, saved))
+	loaded, err = result.Gateway.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, saved, loaded)
+}
+
+func TestOverrides_Load(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "overrides.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# edited by hand\n\nADD qoph\n  common spew  \nadd café\n"), 0o644))
+	result, err := New(Params{Config: Config{Path: path}})
+	require.NoError(t, err)
+
+	loaded, err := result.Gateway.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, entity.Overrides{
+		{Action: entity.OverrideAdd, Word: "QOPH"},
+		{Action: entity.OverrideCommon, Word: "SPEW"},
+		{Action: entity.OverrideAdd, Word: "CAFE"},
+	}, loaded)
+
+	require.NoError(t, os.WriteFile(path, []byte("add QOPH\nadd r2d2\n"), 0o644))
+	_, err = result.Gateway.Load(ctx)
+	assert.ErrorContains(t, err, "line 2")
+
+	require.NoError(t, os.WriteFile(path, []byte("add\nrename SPEW SPUE\n"), 0o644))
+	_, err = result.Gateway.Load(ctx)
+	assert.ErrorContains(t, err, "line 1")
+}
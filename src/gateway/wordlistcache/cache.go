@@ -0,0 +1,120 @@
+package wordlistcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/fx"
+)
+
+const fileExtension = ".dag"
+
+var Module = fx.Module("wordlistcache",
+	fx.Provide(New),
+)
+
+// Gateway stores serialized word lists by key
+type Gateway interface {
+	// Get returns false if nothing is cached for key
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+type Config struct {
+	// Dir holds cached word lists. Defaults to bongo/wordlist under the user cache directory.
+	Dir string
+	// Disabled turns caching off, so every run rebuilds the word list
+	Disabled bool
+}
+
+type Params struct {
+	fx.In
+
+	Config Config `optional:"true"`
+}
+
+type Result struct {
+	fx.Out
+
+	Gateway
+}
+
+type gateway struct {
+	dir string
+}
+
+type noopGateway struct{}
+
+func New(p Params) (Result, error) {
+	if p.Config.Disabled {
+		return Result{Gateway: noopGateway{}}, nil
+	}
+	dir := p.Config.Dir
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			// Not worth failing over, just rebuild every time
+			slog.Warn("no user cache directory, word list cache disabled", "err", err)
+			return Result{Gateway: noopGateway{}}, nil
+		}
+		dir = filepath.Join(cacheDir, "bongo", "wordlist")
+	}
+	return Result{
+		Gateway: &gateway{dir: dir},
+	}, nil
+}
+
+func (g *gateway) Get(_ context.Context, key string) ([]byte, bool, error) {
+	raw, err := os.ReadFile(g.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to read word list cache %w", err)
+	}
+	return raw, true, nil
+}
+
+func (g *gateway) Put(_ context.Context, key string, data []byte) error {
+	if err := os.MkdirAll(g.dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create word list cache %w", err)
+	}
+	// Write to a temp file first so a concurrent run never reads half a list
+	tmp, err := os.CreateTemp(g.dir, key+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create word list cache %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write word list cache %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write word list cache %w", err)
+	}
+	if err := os.Rename(tmp.Name(), g.path(key)); err != nil {
+		return fmt.Errorf("unable to write word list cache %w", err)
+	}
+	slog.Debug("cached word list",
+		"path", g.path(key),
+		"bytes", len(data),
+	)
+	return nil
+}
+
+func (g *gateway) path(key string) string {
+	return filepath.Join(g.dir, key+fileExtension)
+}
+
+func (noopGateway) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, nil
+}
+
+func (noopGateway) Put(context.Context, string, []byte) error {
+	return nil
+}
@@ -2,26 +2,97 @@ package wordlistimporter
 
 import (
 	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
-	"runtime"
+	"slices"
 	"strings"
+	"unicode"
 
 	"go.uber.org/fx"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 )
 
-const path = "../../../../words/bongo/commonWords.txt"
+// StdinSource reads a word list from standard input
+const StdinSource = "-"
 
-var wordRegex = regexp.MustCompile(`^(\w{3,5})$`)
+// Playable word lengths are up to the rules, which the word list builder applies
+var wordRegex = regexp.MustCompile(`^[A-Z]+$`)
+
+// defaultWords is the list used when no sources are configured, one word per line. words.txt is
+// committed so plain builds and go install have a list: a starter list of common English words.
+// go generate replaces it with bongo/commonWords.txt from Puzzmo's word list repository
+// (github.com/puzzmo-com/words, checked out as the words submodule; see it for terms of use),
+// since go:embed can't reach outside this package.
+//
+//go:generate cp ../../../words/bongo/commonWords.txt words.txt
+//go:embed words.txt
+var defaultWords string
+
+// ErrNoDefaultWords means the binary was built without its default word list
+var ErrNoDefaultWords = errors.New("embedded word list is empty, run go generate ./... with the words submodule checked out or pass -words")
 
 var Module = fx.Module("wordimporter",
 	fx.Provide(New),
 )
 
 type Gateway interface {
-	ImportWordList(ctx context.Context) ([]string, error)
+	// ImportWordList returns the normalized words, sorted and without repeats, along with the
+	// lines that were left out
+	ImportWordList(ctx context.Context) ([]string, *Report, error)
+}
+
+// Reason is why an imported line was left out of the word list
+type Reason string
+
+const (
+	// ReasonInvalid lines have something other than A-Z once normalized
+	ReasonInvalid Reason = "invalid"
+	// ReasonDuplicate lines normalize to a word already imported, from any source
+	ReasonDuplicate Reason = "duplicate"
+)
+
+// Rejection is a line left out of the word list
+type Rejection struct {
+	Source string
+	// Line is 1-indexed within the source
+	Line   int
+	Text   string
+	Reason Reason
+}
+
+// Report lists the lines left out of an import. Blank lines are skipped without a report.
+type Report struct {
+	Rejections []Rejection
+}
+
+// Counts is the number of rejections for each reason
+func (r *Report) Counts() map[Reason]int {
+	counts := map[Reason]int{}
+	for _, rejection := range r.Rejections {
+		counts[rejection.Reason]++
+	}
+	return counts
+}
+
+// Config selects where words are read from
+type Config struct {
+	// Sources are word list files, directories whose files are all merged, or StdinSource.
+	// The embedded default list is used if empty.
+	Sources []string
+}
+
+type Params struct {
+	fx.In
+
+	Config Config `optional:"true"`
 }
 
 type Result struct {
@@ -31,31 +102,157 @@ type Result struct {
 }
 
 type gateway struct {
+	sources []string
+	stdin   io.Reader
+	// embedded is the list used without sources
+	embedded string
 }
 
-func New() (Result, error) {
+func New(p Params) (Result, error) {
 	return Result{
-		Gateway: &gateway{},
+		Gateway: &gateway{
+			sources:  p.Config.Sources,
+			stdin:    os.Stdin,
+			embedded: defaultWords,
+		},
 	}, nil
 }
 
-func (g *gateway) ImportWordList(ctx context.Context) ([]string, error) {
-	_, file, _, _ := runtime.Caller(0)
-	path := filepath.Join(file, path)
-	raw, err := os.ReadFile(path)
+func (g *gateway) ImportWordList(ctx context.Context) ([]string, *Report, error) {
+	p := newParser()
+	if len(g.sources) == 0 {
+		if strings.TrimSpace(g.embedded) == "" {
+			return nil, nil, ErrNoDefaultWords
+		}
+		p.parse("embedded", g.embedded)
+		slog.Debug("loaded word list",
+			"source", "embedded",
+			"word_count", len(p.words),
+		)
+		return p.result()
+	}
+
+	for _, source := range g.sources {
+		before := len(p.words)
+		if err := g.importSource(p, source); err != nil {
+			return nil, nil, err
+		}
+		slog.Debug("loaded word list",
+			"source", source,
+			"word_count", len(p.words)-before,
+		)
+	}
+	return p.result()
+}
+
+func (g *gateway) importSource(p *parser, source string) error {
+	if source == StdinSource {
+		raw, err := io.ReadAll(g.stdin)
+		if err != nil {
+			return fmt.Errorf("unable to read word list from stdin %w", err)
+		}
+		p.parse("stdin", string(raw))
+		return nil
+	}
+
+	info, err := os.Stat(source)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("unable to open word list %w", err)
+	}
+	if !info.IsDir() {
+		raw, err := os.ReadFile(source)
+		if err != nil {
+			return fmt.Errorf("unable to read word list %w", err)
+		}
+		p.parse(source, string(raw))
+		return nil
+	}
+
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return fmt.Errorf("unable to read word list directory %w", err)
+	}
+	for _, entry := range entries {
+		// Skip nested directories and dotfiles like .gitkeep
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(source, entry.Name())
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read word list %w", err)
+		}
+		p.parse(path, string(raw))
+	}
+	return nil
+}
+
+// parser collects words across sources, so repeats are caught even between files
+type parser struct {
+	words  []string
+	seen   map[string]bool
+	report *Report
+}
+
+func newParser() *parser {
+	return &parser{
+		words:  []string{},
+		seen:   map[string]bool{},
+		report: &Report{Rejections: []Rejection{}},
 	}
-	rows := strings.Split(strings.ToUpper(string(raw)), "\n")
-	filtered := []string{}
-	for _, word := range rows {
-		if match := wordRegex.FindStringSubmatch(word); len(match) > 0 {
-			filtered = append(filtered, match[1])
+}
+
+func (p *parser) parse(source, raw string) {
+	for i, line := range strings.Split(raw, "\n") {
+		word := normalize(line)
+		if word == "" {
+			continue
+		}
+		reason := Reason("")
+		switch {
+		case !wordRegex.MatchString(word):
+			reason = ReasonInvalid
+		case p.seen[word]:
+			reason = ReasonDuplicate
 		}
+		if reason != "" {
+			p.report.Rejections = append(p.report.Rejections, Rejection{
+				Source: source,
+				Line:   i + 1,
+				Text:   strings.TrimSpace(line),
+				Reason: reason,
+			})
+			continue
+		}
+		p.seen[word] = true
+		p.words = append(p.words, word)
+	}
+}
+
+func (p *parser) result() ([]string, *Report, error) {
+	slices.Sort(p.words)
+	return p.words, p.report, nil
+}
+
+// Normalize puts a word the way the word list has it, or reports that it isn't a word. Overrides
+// are checked with it so they match imported words.
+func Normalize(word string) (string, error) {
+	normalized := normalize(word)
+	if !wordRegex.MatchString(normalized) {
+		return "", fmt.Errorf("invalid word %q, expected only letters A-Z", strings.TrimSpace(word))
+	}
+	return normalized, nil
+}
+
+// normalize trims whitespace (including the \r of CRLF line endings) and byte order marks,
+// strips accents, and uppercases
+func normalize(line string) string {
+	line = strings.TrimSpace(strings.TrimPrefix(line, "\uFEFF"))
+	// Decompose letters and drop the combining marks, so É becomes E. Transformers keep state, so
+	// each call gets its own.
+	stripAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if folded, _, err := transform.String(stripAccents, line); err == nil {
+		line = folded
 	}
-	slog.Debug("loaded word list",
-		"path", path,
-		"word_count", len(filtered),
-	)
-	return filtered, nil
+	return strings.ToUpper(line)
 }
@@ -2,6 +2,10 @@ package wordlistimporter
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,11 +14,84 @@ import (
 
 func TestImport(t *testing.T) {
 	ctx := context.Background()
-	importerGateway, err := New()
-	require.NoError(t, err)
-	words, err := importerGateway.ImportWordList(ctx)
+	importerGateway := &gateway{embedded: "lambs\nspeak\nback\n"}
+	words, report, err := importerGateway.ImportWordList(ctx)
 	assert.NoError(t, err)
-	assert.Contains(t, words, "LAMBS")
-	assert.Contains(t, words, "SPEAK")
-	assert.Contains(t, words, "BACK")
+	assert.Empty(t, report.Rejections)
+	assert.Equal(t, []string{"BACK", "LAMBS", "SPEAK"}, words)
+
+	importerGateway = &gateway{embedded: "\n"}
+	_, _, err = importerGateway.ImportWordList(ctx)
+	assert.ErrorIs(t, err, ErrNoDefaultWords)
+}
+
+// Builds without go generate still ship a usable list
+func TestImport_Embedded(t *testing.T) {
+	ctx := context.Background()
+	result, err := New(Params{})
+	require.NoError(t, err)
+	words, report, err := result.Gateway.ImportWordList(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, report.Rejections)
+	assert.Greater(t, len(words), 1000)
+}
+
+func TestImport_Sources(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("crab\nback\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("BACK\r\nLAMBS\nnot a word\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitkeep"), []byte("hidden\n"), 0o644))
+	file := filepath.Join(t.TempDir(), "extra.txt")
+	require.NoError(t, os.WriteFile(file, []byte("speak\n"), 0o644))
+
+	importerGateway := &gateway{
+		sources: []string{dir, file, StdinSource},
+		stdin:   strings.NewReader("chip\nwhap\n"),
+	}
+	words, _, err := importerGateway.ImportWordList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BACK", "CHIP", "CRAB", "LAMBS", "SPEAK", "WHAP"}, words)
+
+	importerGateway = &gateway{sources: []string{filepath.Join(dir, "missing.txt")}}
+	_, _, err = importerGateway.ImportWordList(ctx)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestImport_Normalize(t *testing.T) {
+	ctx := context.Background()
+	importerGateway := &gateway{
+		sources: []string{StdinSource},
+		stdin:   strings.NewReader("\uFEFFcafé\r\n  Naïve \r\n\r\nCAFE\npiñata\nr2d2\nsnake_case\nstraße\n"),
+	}
+	words, report, err := importerGateway.ImportWordList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CAFE", "NAIVE", "PINATA"}, words)
+	assert.Equal(t, []Rejection{
+		{Source: "stdin", Line: 4, Text: "CAFE", Reason: ReasonDuplicate},
+		{Source: "stdin", Line: 6, Text: "r2d2", Reason: ReasonInvalid},
+		{Source: "stdin", Line: 7, Text: "snake_case", Reason: ReasonInvalid},
+		// ß has no accent to strip
+		{Source: "stdin", Line: 8, Text: "straße", Reason: ReasonInvalid},
+	}, report.Rejections)
+	assert.Equal(t, map[Reason]int{ReasonInvalid: 3, ReasonDuplicate: 1}, report.Counts())
+}
+
+func TestNormalize_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				word, err := Normalize(" Piñata ")
+				assert.NoError(t, err)
+				assert.Equal(t, "PINATA", word)
+			}
+		}()
+	}
+	wg.Wait()
+
+	_, err := Normalize("r2d2")
+	assert.Error(t, err)
 }
@@ -5,7 +5,9 @@ import (
 
 	"go.uber.org/fx"
 
+	"github.com/azhu2/bongo/src/config/dates"
 	"github.com/azhu2/bongo/src/controller/parser"
+	"github.com/azhu2/bongo/src/controller/query"
 	"github.com/azhu2/bongo/src/controller/scorer"
 	"github.com/azhu2/bongo/src/controller/solver"
 	"github.com/azhu2/bongo/src/entity"
@@ -16,16 +18,21 @@ var Module = fx.Module("handler",
 	fx.Provide(New),
 )
 
+// Dates can be anything dates.Resolver accepts, so "" is today
 type Handler interface {
 	Solve(ctx context.Context, date string) ([]entity.Solution, int, error)
+	// Words finds words fitting a pattern. If date is set, they are scored against that day's board.
+	Words(ctx context.Context, date string, q query.Query) ([]query.Match, error)
 }
 
 type Params struct {
 	fx.In
 
+	Dates        dates.Resolver
 	GameImporter gameimporter.Gateway
 
 	Parser parser.Controller
+	Query  query.Controller
 	Scorer scorer.Controller
 	Solver solver.Controller
 }
@@ -37,9 +44,11 @@ type Result struct {
 }
 
 type handler struct {
+	dates        dates.Resolver
 	gameImporter gameimporter.Gateway
 
 	parser parser.Controller
+	query  query.Controller
 	scorer scorer.Controller
 	solver solver.Controller
 }
@@ -47,9 +56,11 @@ type handler struct {
 func New(p Params) (Result, error) {
 	return Result{
 		Handler: &handler{
+			dates:        p.Dates,
 			gameImporter: p.GameImporter,
 
 			parser: p.Parser,
+			query:  p.Query,
 			scorer: p.Scorer,
 			solver: p.Solver,
 		},
@@ -57,6 +68,10 @@ func New(p Params) (Result, error) {
 }
 
 func (h *handler) Solve(ctx context.Context, date string) ([]entity.Solution, int, error) {
+	date, err := h.dates.Resolve(date)
+	if err != nil {
+		return nil, 0, err
+	}
 	boardData, err := h.gameImporter.ImportBoard(ctx, date)
 	if err != nil {
 		return nil, 0, err
@@ -72,6 +87,9 @@ func (h *handler) Solve(ctx context.Context, date string) ([]entity.Solution, in
 	if err != nil {
 		return nil, 0, err
 	}
+	if len(solutions) == 0 {
+		return nil, 0, solver.ErrNoSolution
+	}
 
 	score, err := h.scorer.Score(ctx, board, solutions[0])
 	if err != nil {
@@ -80,3 +98,22 @@ func (h *handler) Solve(ctx context.Context, date string) ([]entity.Solution, in
 
 	return solutions, score, err
 }
+
+func (h *handler) Words(ctx context.Context, date string, q query.Query) ([]query.Match, error) {
+	if date != "" {
+		date, err := h.dates.Resolve(date)
+		if err != nil {
+			return nil, err
+		}
+		boardData, err := h.gameImporter.ImportBoard(ctx, date)
+		if err != nil {
+			return nil, err
+		}
+		board, err := h.parser.ParseBoard(ctx, boardData)
+		if err != nil {
+			return nil, err
+		}
+		q.Board = board
+	}
+	return h.query.Words(ctx, q)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/fx"
+
+	"github.com/azhu2/bongo/src/controller/parser"
+	"github.com/azhu2/bongo/src/controller/query"
+)
+
+var HTTPModule = fx.Module("http",
+	fx.Provide(NewHTTP),
+)
+
+type HTTPParams struct {
+	fx.In
+
+	Handler Handler
+	Parser  parser.Controller
+}
+
+type HTTPResult struct {
+	fx.Out
+
+	http.Handler
+}
+
+type httpHandler struct {
+	handler Handler
+	parser  parser.Controller
+}
+
+// NewHTTP serves the handler as a JSON API:
+//
+//	GET /solve?date=2024-12-24
+//	GET /words?pattern=S?O?N&tiles=SWORN&date=2024-12-24&row=0
+func NewHTTP(p HTTPParams) (HTTPResult, error) {
+	h := &httpHandler{
+		handler: p.Handler,
+		parser:  p.Parser,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /solve", h.solve)
+	mux.HandleFunc("GET /words", h.words)
+	return HTTPResult{
+		Handler: mux,
+	}, nil
+}
+
+type solveResponse struct {
+	Score     int      `json:"score"`
+	Solutions []string `json:"solutions"`
+}
+
+func (h *httpHandler) solve(w http.ResponseWriter, r *http.Request) {
+	solutions, score, err := h.handler.Solve(r.Context(), r.URL.Query().Get("date"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	response := solveResponse{Score: score}
+	for _, solution := range solutions {
+		serialized, err := h.parser.SerializeSolution(r.Context(), solution, parser.FormatText)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		response.Solutions = append(response.Solutions, serialized)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *httpHandler) words(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	q := query.Query{Pattern: params.Get("pattern")}
+	if params.Has("tiles") {
+		q.Tiles = query.ParseTiles(params.Get("tiles"))
+	}
+	if params.Has("row") {
+		row, err := strconv.Atoi(params.Get("row"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		q.Row = row
+	}
+	matches, err := h.handler.Words(r.Context(), params.Get("date"), q)
+	if errors.Is(err, query.InvalidQueryError{}) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, matches)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Error("unable to write response", "err", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -2,58 +2,113 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log/slog"
-	"time"
+	"os"
 
-	"github.com/machinebox/graphql"
 	"go.uber.org/fx"
 
-	"github.com/azhu2/bongo/src/config/secrets"
+	"github.com/azhu2/bongo/src/config/dates"
 	"github.com/azhu2/bongo/src/controller/parser"
 	"github.com/azhu2/bongo/src/controller/scorer"
-	"github.com/azhu2/bongo/src/controller/solver"
 	"github.com/azhu2/bongo/src/controller/wordlist"
 	"github.com/azhu2/bongo/src/entity"
 	"github.com/azhu2/bongo/src/gateway/gameimporter"
-	"github.com/azhu2/bongo/src/handler"
+	"github.com/azhu2/bongo/src/gateway/overrides"
+	"github.com/azhu2/bongo/src/gateway/wordlistcache"
+	"github.com/azhu2/bongo/src/gateway/wordlistimporter"
 )
 
 func main() {
+	formatFlag := flag.String("format", string(parser.FormatText), "output format for solutions (text, json, yaml)")
+	rules := entity.DefaultRules()
+	flag.IntVar(&rules.MaxWildcards, "wildcards", rules.MaxWildcards, "number of letters that can be played without a tile")
+	flag.Float64Var(&rules.CommonMultiplier, "common-multiplier", rules.CommonMultiplier, "score multiplier for common words")
+	flag.IntVar(&rules.MinWordLength, "min-word-length", rules.MinWordLength, "shortest playable word")
+	flag.IntVar(&rules.MaxWordLength, "max-word-length", rules.MaxWordLength, "longest playable word")
+	flag.BoolVar(&rules.OneWordPerRow, "one-word-per-row", rules.OneWordPerRow, "only score rows that form a single word (solving requires it)")
+	wordsConfig := wordlistimporter.Config{}
+	flag.Func("words", "word list file or directory to merge, or - for stdin (repeatable, default embedded list)", func(source string) error {
+		wordsConfig.Sources = append(wordsConfig.Sources, source)
+		return nil
+	})
+	cacheConfig := wordlistcache.Config{}
+	flag.StringVar(&cacheConfig.Dir, "cache-dir", "", "directory for the built word list cache (default user cache directory)")
+	flag.BoolVar(&cacheConfig.Disabled, "no-cache", false, "rebuild the word list instead of using the cache")
+	overridesConfig := overrides.Config{}
+	flag.StringVar(&overridesConfig.Path, "overrides", "", "word list overrides file (default bongo/overrides.txt in the user config directory)")
+	archiveConfig := gameimporter.ArchiveConfig{}
+	flag.StringVar(&archiveConfig.Dir, "archive-dir", "", "directory to save imported boards in and serve them from (default user cache directory)")
+	flag.BoolVar(&archiveConfig.Disabled, "no-archive", false, "always fetch boards instead of using the archive")
+	fileConfig := gameimporter.FileConfig{}
+	flag.StringVar(&fileConfig.Dir, "boards-dir", "", "directory of boards named by date, like 2024-12-23.txt, to use before fetching from Puzzmo")
+	graphqlConfig := gameimporter.DefaultGraphqlConfig()
+	flag.StringVar(&graphqlConfig.Endpoint, "endpoint", graphqlConfig.Endpoint, "Puzzmo GraphQL API to import boards from")
+	flag.IntVar(&graphqlConfig.Attempts, "request-attempts", graphqlConfig.Attempts, "times to try a Puzzmo request that fails with a transient error")
+	flag.DurationVar(&graphqlConfig.Timeout, "request-timeout", graphqlConfig.Timeout, "time limit for each Puzzmo request")
+	selection := gameimporter.Selection{}
+	flag.Func("puzzle", "which of the day's bongo puzzles to import, by slug, URL path, index or a finder key like today:/{date}/bongo (default first, see puzzles)", func(value string) error {
+		selection = gameimporter.ParseSelection(value)
+		return nil
+	})
+	datesConfig := dates.Config{}
+	flag.StringVar(&datesConfig.TimeZone, "timezone", dates.DefaultTimeZone, "time zone where Puzzmo's day starts")
+	flag.Usage = usage
+	flag.Parse()
+	format, err := parser.ParseFormat(*formatFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := rules.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// Solving is the default when no command is given
+	name, args := "solve", flag.Args()
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n", name)
+		usage()
+		os.Exit(2)
+	}
+	cmdFlags := flag.NewFlagSet(name, flag.ExitOnError)
+	cmdOptions := cmd.setup(cmdFlags, format)
+	cmdFlags.Parse(args)
+
 	slog.SetLogLoggerLevel(slog.LevelDebug)
 	fx.New(
 		wordlist.Module,
-		handler.Module,
-		gameimporter.GraphqlModule,
 		parser.Module,
 		scorer.Module,
-		secrets.Module,
-		solver.Module,
 		fx.Supply(
-			graphql.NewClient(gameimporter.GraphqlEndpoint),
+			rules,
+			wordsConfig,
+			cacheConfig,
+			overridesConfig,
+			archiveConfig,
+			fileConfig,
+			graphqlConfig,
+			selection,
+			datesConfig,
 		),
 		fx.Provide(func(c wordlist.Controller) (*entity.WordList, error) {
 			return c.BuildWordList(context.Background())
 		}),
-		fx.Invoke(func(lifecycle fx.Lifecycle, shutdowner fx.Shutdowner, handler handler.Handler) {
-			lifecycle.Append(fx.StartHook(func(_ context.Context) {
-				go func() {
-					start := time.Now()
-					// Explicit date not needed if loading from daily screen
-					// serverTime, _ := time.LoadLocation("America/Chicago")
-					// time.Now().In(serverTime).Format("2006-01-02")
-					solutions, score, err := handler.Solve(context.Background(), "")
-					if err != nil {
-						slog.Error("error in solver",
-							"err", err,
-						)
-					}
-					slog.Info("solution found", "score", score, "time", time.Since(start))
-					for _, solution := range solutions {
-						slog.Info(solution.String())
-					}
-					shutdowner.Shutdown()
-				}()
-			}))
-		}),
+		cmdOptions(),
 	).Run()
 }
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [command] [command flags]\n\nCommands:\n", os.Args[0])
+	for _, name := range commandNames {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %-8s%s\n", name, commands[name].description)
+	}
+	fmt.Fprintf(flag.CommandLine.Output(), "\nFlags:\n")
+	flag.PrintDefaults()
+}
@@ -0,0 +1,27 @@
+// Package sleep waits in a way that can be cancelled
+package sleep
+
+import (
+	"context"
+	"time"
+)
+
+// For waits for d, or returns early if ctx is done
+func For(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Until waits until t, or returns early if ctx is done
+func Until(ctx context.Context, t time.Time) error {
+	return For(ctx, time.Until(t))
+}
@@ -0,0 +1,24 @@
+package sleep
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFor(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+	assert.NoError(t, For(ctx, 10*time.Millisecond))
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	assert.NoError(t, Until(ctx, time.Now().Add(-time.Second)), "already past")
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	start = time.Now()
+	assert.ErrorIs(t, For(cancelled, time.Minute), context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.ErrorIs(t, For(cancelled, 0), context.Canceled)
+}
@@ -1,7 +1,10 @@
 package testdata
 
 import (
+	"context"
+
 	"github.com/azhu2/bongo/src/entity"
+	"github.com/azhu2/bongo/src/gateway/wordlistimporter"
 )
 
 type testCase struct {
@@ -15,6 +18,7 @@ var TestData = []testCase{
 	{
 		Date: "2024-12-23",
 		Board: &entity.Board{
+			Size: entity.Size{Rows: 5, Cols: 5},
 			Tiles: map[rune]entity.Tile{
 				'W': entity.Tile{Value: 65, Count: 1},
 				'H': entity.Tile{Value: 40, Count: 1},
@@ -44,11 +48,11 @@ var TestData = []testCase{
 				[]int{3, 3},
 			},
 		},
-		Solution: []rune(
-			"SWORN" +
-				"SHAME" +
-				"PLANE" +
-				"SEEPS" +
+		Solution: entity.NewSolution(entity.Size{Rows: 5, Cols: 5},
+			"SWORN"+
+				"SHAME"+
+				"PLANE"+
+				"SEEPS"+
 				"REEDY",
 		),
 		Score: 1265,
@@ -56,6 +60,7 @@ var TestData = []testCase{
 	{
 		Date: "2024-12-24",
 		Board: &entity.Board{
+			Size: entity.Size{Rows: 5, Cols: 5},
 			Tiles: map[rune]entity.Tile{
 				'G': entity.Tile{Value: 45, Count: 2},
 				'P': entity.Tile{Value: 35, Count: 1},
@@ -85,13 +90,42 @@ var TestData = []testCase{
 				[]int{3, 1},
 			},
 		},
-		Solution: []rune(
-			"OCTAL" +
-				"CHAAT" +
-				"CIGAR" +
-				" PONY" +
+		Solution: entity.NewSolution(entity.Size{Rows: 5, Cols: 5},
+			"OCTAL"+
+				"CHAAT"+
+				"CIGAR"+
+				" PONY"+
 				" SING",
 		),
 		Score: 976,
 	},
 }
+
+// Words are the words played in TestData's solutions, rows then bonus words, for building a word
+// list that scores them
+var Words = []string{
+	"SWORN", "SHAME", "PLANE", "SEEPS", "REEDY", "WHAP",
+	"OCTAL", "CHAAT", "CIGAR", "PONY", "SING", "CHIP",
+}
+
+// StaticWordList is a word list importer that always imports the same words
+type StaticWordList []string
+
+func (w StaticWordList) ImportWordList(_ context.Context) ([]string, *wordlistimporter.Report, error) {
+	return w, &wordlistimporter.Report{}, nil
+}
+
+// StaticOverrides is an overrides gateway that always loads the same overrides
+type StaticOverrides entity.Overrides
+
+func (o StaticOverrides) Load(_ context.Context) (entity.Overrides, error) {
+	return entity.Overrides(o), nil
+}
+
+func (o StaticOverrides) Save(_ context.Context, _ entity.Overrides) error {
+	return nil
+}
+
+func (o StaticOverrides) Path() string {
+	return ""
+}